@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+// Builder accumulates a Document, ProductTree and Vulnerabilities
+// programmatically, for callers that need to construct a Report from data
+// other than a parsed CVRF/CSAF/CVE5/OSV document - e.g. govulncheck scan
+// results, or several CVE 5 records read from a CVEProject/cvelist checkout.
+// The zero Builder is ready to use.
+type Builder struct {
+	doc      Document
+	tree     ProductTree
+	vulns    []Vulnerability
+	vulnKeys map[string]bool
+}
+
+// NewBuilder returns a Builder whose resulting Report carries doc as its
+// Document metadata.
+func NewBuilder(doc Document) *Builder {
+	return &Builder{doc: doc}
+}
+
+// AddProduct records that vendor/product/version is a product the report
+// can refer to, and returns the ProductID assigned to it. Calling
+// AddProduct again with the same vendor, product and version returns the
+// same ProductID rather than adding a duplicate branch.
+func (b *Builder) AddProduct(vendor, product, version string) string {
+	productID := vendor + ":" + product + "@" + version
+
+	for i := range b.tree.Branches {
+		vb := &b.tree.Branches[i]
+		if vb.Type != "Vendor" || vb.Name != vendor {
+			continue
+		}
+		for j := range vb.Branches {
+			pb := &vb.Branches[j]
+			if pb.Type != "Product" || pb.Name != product {
+				continue
+			}
+			for _, verb := range pb.Branches {
+				if verb.Product != nil && verb.Product.ProductID == productID {
+					return productID
+				}
+			}
+			pb.Branches = append(pb.Branches, versionLeaf(version, productID))
+			return productID
+		}
+		vb.Branches = append(vb.Branches, Branch{
+			Type:     "Product",
+			Name:     product,
+			Branches: []Branch{versionLeaf(version, productID)},
+		})
+		return productID
+	}
+
+	b.tree.Branches = append(b.tree.Branches, Branch{
+		Type: "Vendor",
+		Name: vendor,
+		Branches: []Branch{{
+			Type:     "Product",
+			Name:     product,
+			Branches: []Branch{versionLeaf(version, productID)},
+		}},
+	})
+	return productID
+}
+
+func versionLeaf(version, productID string) Branch {
+	return Branch{
+		Type: "Version",
+		Name: version,
+		Product: &FullProductName{
+			Name:      version,
+			ProductID: productID,
+		},
+	}
+}
+
+// AddVulnerability appends vuln to the Report under construction.
+func (b *Builder) AddVulnerability(vuln Vulnerability) {
+	if b.vulnKeys != nil {
+		if key := vulnKey(vuln); key != "" {
+			b.vulnKeys[key] = true
+		}
+	}
+	vuln.Ordinal = len(b.vulns)
+	b.vulns = append(b.vulns, vuln)
+}
+
+// vulnKey identifies a Vulnerability for the purposes of Merge's
+// deduplication: its CVE id when it has one, falling back to its tracking
+// ID.
+func vulnKey(vuln Vulnerability) string {
+	if vuln.CVE != "" {
+		return vuln.CVE
+	}
+	return vuln.ID
+}
+
+// Merge folds rep's Vulnerabilities and ProductTree into the Report under
+// construction. Vulnerabilities already present (matched by CVE id, or
+// tracking ID when the CVE id is empty) are skipped rather than duplicated;
+// ProductTree branches are merged by Type and Name at each level, so the
+// same vendor/product/version reached from two different source reports
+// collapses into a single branch.
+func (b *Builder) Merge(rep Report) {
+	if b.vulnKeys == nil {
+		b.vulnKeys = map[string]bool{}
+		for _, v := range b.vulns {
+			b.vulnKeys[vulnKey(v)] = true
+		}
+	}
+
+	for _, v := range rep.Vulnerabilities {
+		key := vulnKey(v)
+		if key != "" && b.vulnKeys[key] {
+			continue
+		}
+		if key != "" {
+			b.vulnKeys[key] = true
+		}
+		b.AddVulnerability(v)
+	}
+
+	b.tree.Branches = mergeBranches(b.tree.Branches, rep.ProductTree.Branches)
+	b.tree.FullProductNames = mergeFullProductNames(b.tree.FullProductNames, rep.ProductTree.FullProductNames)
+	b.tree.Relationships = append(b.tree.Relationships, rep.ProductTree.Relationships...)
+	b.tree.Groups = mergeGroups(b.tree.Groups, rep.ProductTree.Groups)
+}
+
+// mergeBranches merges adds into existing, matching branches by Type and
+// Name and recursing into their sub-branches, rather than appending
+// duplicates.
+func mergeBranches(existing, adds []Branch) []Branch {
+	for _, add := range adds {
+		existing = mergeBranch(existing, add)
+	}
+	return existing
+}
+
+func mergeBranch(existing []Branch, add Branch) []Branch {
+	for i := range existing {
+		if existing[i].Type != add.Type || existing[i].Name != add.Name {
+			continue
+		}
+		existing[i].Branches = mergeBranches(existing[i].Branches, add.Branches)
+		if existing[i].Product == nil {
+			existing[i].Product = add.Product
+		}
+		return existing
+	}
+	return append(existing, add)
+}
+
+func mergeFullProductNames(existing, adds []FullProductName) []FullProductName {
+	seen := map[string]bool{}
+	for _, fpn := range existing {
+		seen[fpn.ProductID] = true
+	}
+	for _, fpn := range adds {
+		if seen[fpn.ProductID] {
+			continue
+		}
+		seen[fpn.ProductID] = true
+		existing = append(existing, fpn)
+	}
+	return existing
+}
+
+// mergeGroups merges adds into existing, skipping any Group whose GroupID
+// already appears in existing rather than appending a duplicate.
+func mergeGroups(existing, adds []Group) []Group {
+	seen := map[string]bool{}
+	for _, g := range existing {
+		seen[g.GroupID] = true
+	}
+	for _, g := range adds {
+		if seen[g.GroupID] {
+			continue
+		}
+		seen[g.GroupID] = true
+		existing = append(existing, g)
+	}
+	return existing
+}
+
+// Report returns the Report assembled so far.
+func (b *Builder) Report() Report {
+	return Report{
+		Doc:             b.doc,
+		ProductTree:     b.tree,
+		Vulnerabilities: b.vulns,
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import "testing"
+
+func TestBuilderAddProductDedup(t *testing.T) {
+	b := NewBuilder(Document{Title: "test"})
+
+	id1 := b.AddProduct("example", "productx", "1.0.0")
+	id2 := b.AddProduct("example", "productx", "1.0.0")
+	if id1 != id2 {
+		t.Fatalf("AddProduct returned different IDs for the same vendor/product/version: %q vs %q", id1, id2)
+	}
+
+	id3 := b.AddProduct("example", "productx", "2.0.0")
+	if id3 == id1 {
+		t.Fatalf("AddProduct returned the same ID for a different version")
+	}
+
+	rep := b.Report()
+	if len(rep.ProductTree.Branches) != 1 {
+		t.Fatalf("got %d vendor branches, want 1", len(rep.ProductTree.Branches))
+	}
+	productBranch := rep.ProductTree.Branches[0].Branches[0]
+	if len(productBranch.Branches) != 2 {
+		t.Fatalf("got %d version branches, want 2 (no duplicate added)", len(productBranch.Branches))
+	}
+}
+
+func TestBuilderMergeDedupesVulnerabilities(t *testing.T) {
+	b := NewBuilder(Document{Title: "test"})
+	b.AddVulnerability(Vulnerability{ID: "GO-2024-0001", CVE: "CVE-2024-0001"})
+
+	other := Report{
+		Vulnerabilities: []Vulnerability{
+			{ID: "GO-2024-0001", CVE: "CVE-2024-0001"},
+			{ID: "GO-2024-0002", CVE: "CVE-2024-0002"},
+		},
+	}
+	b.Merge(other)
+
+	rep := b.Report()
+	if len(rep.Vulnerabilities) != 2 {
+		t.Fatalf("got %d vulnerabilities, want 2 (duplicate CVE-2024-0001 should be skipped)", len(rep.Vulnerabilities))
+	}
+}
+
+func TestBuilderMergeCombinesProductTree(t *testing.T) {
+	b := NewBuilder(Document{Title: "test"})
+	b.AddProduct("example", "productx", "1.0.0")
+
+	other := Report{
+		ProductTree: ProductTree{
+			Branches: []Branch{
+				{
+					Type: "Vendor",
+					Name: "example",
+					Branches: []Branch{
+						{
+							Type: "Product",
+							Name: "productx",
+							Branches: []Branch{
+								{
+									Type:    "Version",
+									Name:    "2.0.0",
+									Product: &FullProductName{Name: "2.0.0", ProductID: "example:productx@2.0.0"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	b.Merge(other)
+
+	rep := b.Report()
+	if len(rep.ProductTree.Branches) != 1 {
+		t.Fatalf("got %d vendor branches, want 1 (merged, not duplicated)", len(rep.ProductTree.Branches))
+	}
+	productBranch := rep.ProductTree.Branches[0].Branches[0]
+	if len(productBranch.Branches) != 2 {
+		t.Fatalf("got %d version branches, want 2", len(productBranch.Branches))
+	}
+}
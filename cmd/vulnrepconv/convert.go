@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+// Usage: vulnrepconv convert -input <fname> [-output <fname>]
+//
+// The convert command converts a vulnerability report to/from CVRF(xml), CSAF(json),
+// CVE Record Format v5.0 (json) and OSV schema 1.x (json) formats. Note that there
+// are some features of the JSON formats not available in the XML format (notably,
+// translation support), so this tool cannot fully convert a document from
+// JSON --> XML --> JSON, but it will get about as close as possible. Round trips
+// from XML --> JSON --> XML will work. See the Limitations section for details.
+//
+// The input file is required. If no output file is indicated, then the output is
+// written to STDOUT.
+//
+// The type of file is assumed based on file extension: ".xml" for CVRF, ".json"
+// for CSAF, ".cve.json" for CVE Record Format v5.0, and ".osv.json" for OSV. Use
+// the -format flag (cvrf, csaf, cve5, or osv) to override this, e.g. when the
+// extension is ambiguous.
+//
+// Converting a CVE 5 record to CVRF/CSAF produces a single-vulnerability document;
+// converting a multi-vulnerability CVRF/CSAF document to CVE 5 is lossy, since a
+// CVE Record describes exactly one CVE and carries no notion of multiple CNA
+// containers - only the first vulnerability is represented.
+//
+// A CVRF/CSAF document with more than one vulnerability converts to OSV as an
+// NDJSON stream, one entry per line. Pass -osv-split to instead write one
+// ".json" file per vulnerability into the -output directory.
+//
+// Limitations
+//
+// Round trip conversions from XML-->JSON-->XML work, but may not be identical, due
+// to a number of factors:
+// - namespace prefixes might change
+// - whitespaces might change
+// - attribute ordering might change
+// - xml:lang attributes will be discarded
+// - CDATA and comments may change
+//
+// A carefully constructed XML file, however, will be able to successfully round-trip
+// back to a byte-for-byte identical file.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TIBCOSoftware/vulnrep"
+)
+
+// format names a serialization vulnrepconv knows how to read or write.
+// The empty format means "infer from file extension".
+type format string
+
+const (
+	formatAuto = format("")
+	formatCVRF = format("cvrf")
+	formatCSAF = format("csaf")
+	formatCVE5 = format("cve5")
+	formatOSV  = format("osv")
+)
+
+type toConvert struct {
+	input        string
+	output       string
+	format       string
+	osvSplit     bool
+	help         bool
+	dontContinue bool
+}
+
+func (tc *toConvert) parseArgs(appName string, args []string) error {
+
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	fs.StringVar(&tc.input, "input", "", "file to parse for conversion")
+	fs.StringVar(&tc.output, "output", "", "output destination")
+	fs.StringVar(&tc.format, "format", "", "force the input format instead of inferring it from the file extension: cvrf, csaf, cve5, or osv")
+	fs.BoolVar(&tc.osvSplit, "osv-split", false, "when writing OSV, write one file per vulnerability into the -output directory instead of an NDJSON stream")
+	fs.BoolVar(&tc.help, "h", false, "set this flag for help.")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if tc.help {
+		fs.PrintDefaults()
+		tc.dontContinue = true
+		return nil
+	}
+	if tc.input == "" {
+		return fmt.Errorf("must specify an input file with the -input parameter")
+	}
+
+	return nil
+}
+
+func parseXMLFile(fName string) (vulnrep.Report, error) {
+	// no security risk to parsing XML here.
+	f, err := os.Open(fName) //nolint:gosec
+	if err != nil {
+		return vulnrep.Report{}, err
+	}
+	defer safeClose(f)
+
+	return vulnrep.ParseXML(f)
+
+}
+func parseJSONFile(fName string) (vulnrep.Report, error) {
+	// no security risk to parsing JSON here.
+	f, err := os.Open(fName) //nolint:gosec
+	if err != nil {
+		return vulnrep.Report{}, err
+	}
+	defer safeClose(f)
+
+	return vulnrep.ParseJSON(f)
+
+}
+
+func parseCVE5File(fName string) (vulnrep.Report, error) {
+	// no security risk to parsing JSON here.
+	f, err := os.Open(fName) //nolint:gosec
+	if err != nil {
+		return vulnrep.Report{}, err
+	}
+	defer safeClose(f)
+
+	return vulnrep.ParseCVE5(f)
+}
+
+// parseOSVFile reads fName as an OSV document, which may be either a
+// single entry or an NDJSON stream of several (the shape Report.ToOSV
+// writes for a multi-vulnerability report). Entries are parsed one at a
+// time with vulnrep.ParseOSV and merged together with a Builder, per the
+// usage ParseOSV's doc comment recommends.
+func parseOSVFile(fName string) (vulnrep.Report, error) {
+	// no security risk to parsing JSON here.
+	f, err := os.Open(fName) //nolint:gosec
+	if err != nil {
+		return vulnrep.Report{}, err
+	}
+	defer safeClose(f)
+
+	var builder *vulnrep.Builder
+	dec := json.NewDecoder(f)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return vulnrep.Report{}, err
+		}
+
+		entryRep, err := vulnrep.ParseOSV(bytes.NewReader(raw))
+		if err != nil {
+			return vulnrep.Report{}, err
+		}
+		if builder == nil {
+			builder = vulnrep.NewBuilder(entryRep.Doc)
+		}
+		builder.Merge(entryRep)
+	}
+	if builder == nil {
+		return vulnrep.Report{}, fmt.Errorf("no OSV entries found in %s", fName)
+	}
+
+	return builder.Report(), nil
+}
+
+// inputFormat determines which parser to use for fName, preferring an
+// explicit format over the filename's extension. Names ending in
+// ".cve.json" or ".osv.json" are recognized without -format, since they are
+// otherwise indistinguishable from a CSAF ".json" file by extension alone.
+func inputFormat(fName string, explicit format) format {
+	if explicit != formatAuto {
+		return explicit
+	}
+	switch {
+	case strings.HasSuffix(fName, ".cve.json"):
+		return formatCVE5
+	case strings.HasSuffix(fName, ".osv.json"):
+		return formatOSV
+	}
+	switch filepath.Ext(fName) {
+	case ".xml":
+		return formatCVRF
+	case ".json":
+		return formatCSAF
+	default:
+		return formatAuto
+	}
+}
+
+func (tc *toConvert) doConversion() (err error) {
+
+	var readFunc func(string) (vulnrep.Report, error)
+
+	switch inputFormat(tc.input, format(tc.format)) {
+	case formatCVRF:
+		readFunc = parseXMLFile
+	case formatCSAF:
+		readFunc = parseJSONFile
+	case formatCVE5:
+		readFunc = parseCVE5File
+	case formatOSV:
+		readFunc = parseOSVFile
+	default:
+		return fmt.Errorf("unrecognized file extension %v - don't know how to parse file", filepath.Ext(tc.input))
+	}
+
+	rep, err := readFunc(tc.input)
+	if err != nil {
+		return err
+	}
+
+	if tc.osvSplit {
+		return writeOSVSplit(rep, tc.output)
+	}
+
+	outFunc := rep.ToCSAF
+	var out io.Writer
+	if tc.output == "" {
+		out = os.Stdout
+	} else {
+		outFile, err := os.Create(tc.output)
+		if err != nil {
+			return fmt.Errorf("unable to open output file for conversion: %v", err)
+		}
+		defer safeWriteClose(&err, outFile)
+		out = outFile
+
+		switch {
+		case strings.HasSuffix(tc.output, ".cve.json"):
+			outFunc = rep.ToCVE5
+		case strings.HasSuffix(tc.output, ".osv.json"):
+			outFunc = rep.ToOSV
+		case filepath.Ext(tc.output) == ".xml":
+			outFunc = rep.ToCVRF
+		}
+	}
+
+	return outFunc(out)
+}
+
+// writeOSVSplit writes one OSV JSON file per vulnerability in rep into the
+// directory dir, named after the vulnerability's ID (falling back to
+// "entry-N", N being its zero-based position in rep.Vulnerabilities, when
+// the ID is empty). It is the -osv-split counterpart to
+// Report.ToOSV's single NDJSON stream.
+func writeOSVSplit(rep vulnrep.Report, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("must specify a directory with -output when using -osv-split")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create output directory: %v", err)
+	}
+
+	for i, v := range rep.Vulnerabilities {
+		name := v.ID
+		if name == "" {
+			name = fmt.Sprintf("entry-%d", i)
+		}
+		single := rep
+		single.Vulnerabilities = []vulnrep.Vulnerability{v}
+
+		if err := writeOSVEntry(single, filepath.Join(dir, name+".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOSVEntry(rep vulnrep.Report, fName string) (err error) {
+	f, err := os.Create(fName)
+	if err != nil {
+		return fmt.Errorf("unable to open output file for conversion: %v", err)
+	}
+	defer safeWriteClose(&err, f)
+
+	return rep.ToOSV(f)
+}
+
+func (tc *toConvert) run(appName string, args []string) error {
+	err := tc.parseArgs(appName, args)
+	if err != nil {
+		return err
+	}
+
+	if tc.dontContinue {
+		return nil
+	}
+	return tc.doConversion()
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOSVFileMultiEntry(t *testing.T) {
+	ndjson := `{"id":"CVE-2024-1111","summary":"first"}
+{"id":"CVE-2024-2222","summary":"second"}
+`
+	fName := filepath.Join(t.TempDir(), "multi.osv.json")
+	if err := os.WriteFile(fName, []byte(ndjson), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rep, err := parseOSVFile(fName)
+	if err != nil {
+		t.Fatalf("parseOSVFile: %v", err)
+	}
+	if len(rep.Vulnerabilities) != 2 {
+		t.Fatalf("got %d vulnerabilities, want 2", len(rep.Vulnerabilities))
+	}
+	if got, want := rep.Vulnerabilities[0].ID, "CVE-2024-1111"; got != want {
+		t.Errorf("vulnerabilities[0].ID = %q, want %q", got, want)
+	}
+	if got, want := rep.Vulnerabilities[1].ID, "CVE-2024-2222"; got != want {
+		t.Errorf("vulnerabilities[1].ID = %q, want %q", got, want)
+	}
+}
+
+func TestParseOSVFileSingleEntry(t *testing.T) {
+	fName := filepath.Join(t.TempDir(), "single.osv.json")
+	if err := os.WriteFile(fName, []byte(`{"id":"CVE-2024-3333","summary":"only"}`), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	rep, err := parseOSVFile(fName)
+	if err != nil {
+		t.Fatalf("parseOSVFile: %v", err)
+	}
+	if len(rep.Vulnerabilities) != 1 {
+		t.Fatalf("got %d vulnerabilities, want 1", len(rep.Vulnerabilities))
+	}
+	if got, want := rep.Vulnerabilities[0].ID, "CVE-2024-3333"; got != want {
+		t.Errorf("vulnerabilities[0].ID = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+// Usage: vulnrepconv fetch [-cna <name>] [-since <YYYY-MM-DD>] [-match <substring>] [-output <fname>] [CVE-ID ...]
+//
+// The fetch command ingests CVE Record Format v5.0 advisories from a local
+// mirror of https://github.com/CVEProject/cvelist (shallow-cloning or updating
+// it under the user's cache directory on first use), filters them, and emits a
+// single merged CVRF or CSAF document containing every match.
+//
+// With no flags or positional arguments, every record in the mirror is
+// emitted; in practice callers will narrow the walk with one or more of:
+//
+//	-cna      only records assigned by the given CNA (assignerShortName)
+//	-since    only records published on or after the given date (YYYY-MM-DD)
+//	-match    only records naming a vendor or product containing the substring
+//
+// Positional arguments, if given, are specific CVE IDs to fetch; when present,
+// only those IDs are read from the mirror rather than the full tree.
+//
+// The output format is chosen from the -output extension: ".xml" produces
+// CVRF, anything else produces CSAF. Unlike the convert command, ".cve.json"
+// and ".osv.json" are not recognized and also produce CSAF.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TIBCOSoftware/vulnrep"
+)
+
+const cvelistRemote = "https://github.com/CVEProject/cvelist"
+
+type toFetch struct {
+	cacheDir     string
+	cna          string
+	since        string
+	match        string
+	ids          []string
+	output       string
+	help         bool
+	dontContinue bool
+}
+
+func (tf *toFetch) parseArgs(appName string, args []string) error {
+
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	fs.StringVar(&tf.cacheDir, "cache-dir", defaultCVEListCacheDir(), "local directory to clone/update the CVEProject/cvelist mirror into")
+	fs.StringVar(&tf.cna, "cna", "", "only fetch records assigned by this CNA")
+	fs.StringVar(&tf.since, "since", "", "only fetch records published on or after this date (YYYY-MM-DD)")
+	fs.StringVar(&tf.match, "match", "", "only fetch records naming a vendor or product containing this substring")
+	fs.StringVar(&tf.output, "output", "", "output destination")
+	fs.BoolVar(&tf.help, "h", false, "set this flag for help.")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if tf.help {
+		fs.PrintDefaults()
+		tf.dontContinue = true
+		return nil
+	}
+	tf.ids = fs.Args()
+
+	return nil
+}
+
+func (tf *toFetch) run(appName string, args []string) error {
+	err := tf.parseArgs(appName, args)
+	if err != nil {
+		return err
+	}
+
+	if tf.dontContinue {
+		return nil
+	}
+	return tf.doFetch()
+}
+
+func (tf *toFetch) doFetch() (err error) {
+	var since time.Time
+	if tf.since != "" {
+		since, err = time.Parse("2006-01-02", tf.since)
+		if err != nil {
+			return fmt.Errorf("invalid -since date %q: %v", tf.since, err)
+		}
+	}
+
+	if err := syncCVEList(tf.cacheDir); err != nil {
+		return err
+	}
+
+	matched, err := walkCVEList(tf.cacheDir, tf.ids, func(rep vulnrep.Report) bool {
+		return matchesCriteria(rep, tf.cna, since, tf.match)
+	})
+	if err != nil {
+		return err
+	}
+
+	builder := vulnrep.NewBuilder(vulnrep.Document{
+		Title: "Merged CVEProject/cvelist advisories",
+		Type:  "security_advisory",
+	})
+	for _, rep := range matched {
+		builder.Merge(rep)
+	}
+	rep := builder.Report()
+
+	outFunc := rep.ToCSAF
+	var out io.Writer
+	if tf.output == "" {
+		out = os.Stdout
+	} else {
+		outFile, err := os.Create(tf.output)
+		if err != nil {
+			return fmt.Errorf("unable to open output file for fetch results: %v", err)
+		}
+		defer safeWriteClose(&err, outFile)
+		out = outFile
+		if filepath.Ext(tf.output) == ".xml" {
+			outFunc = rep.ToCVRF
+		}
+	}
+
+	return outFunc(out)
+}
+
+// syncCVEList shallow-clones https://github.com/CVEProject/cvelist into dir,
+// or fast-forwards it if already present.
+func syncCVEList(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		cmd := exec.Command("git", "-C", dir, "pull", "--ff-only", "--depth", "1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("updating cvelist mirror: %v: %s", err, out)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return fmt.Errorf("creating cvelist cache directory: %v", err)
+	}
+	cmd := exec.Command("git", "clone", "--depth", "1", cvelistRemote, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cloning cvelist mirror: %v: %s", err, out)
+	}
+	return nil
+}
+
+// walkCVEList parses every CVE Record Format v5.0 JSON file under dir's
+// "cves/<year>/<Nxxx>/" tree - the layout CVEProject/cvelist publishes -
+// calling keep on each parsed Report to decide whether to include it. If
+// ids is non-empty, only files named after one of those CVE IDs are read.
+func walkCVEList(dir string, ids []string, keep func(vulnrep.Report) bool) ([]vulnrep.Report, error) {
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var matched []vulnrep.Report
+	root := filepath.Join(dir, "cves")
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		cveID := strings.TrimSuffix(filepath.Base(path), ".json")
+		if len(wanted) > 0 && !wanted[cveID] {
+			return nil
+		}
+
+		rep, err := parseCVE5PathFile(path)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if keep(rep) {
+			matched = append(matched, rep)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("walking cvelist mirror: %w", walkErr)
+	}
+	return matched, nil
+}
+
+func parseCVE5PathFile(path string) (vulnrep.Report, error) {
+	// no security risk to parsing JSON here.
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return vulnrep.Report{}, err
+	}
+	defer safeClose(f)
+
+	return vulnrep.ParseCVE5(f)
+}
+
+// matchesCriteria reports whether rep should be included given the -cna,
+// -since and -match filters. An empty filter always matches.
+func matchesCriteria(rep vulnrep.Report, cna string, since time.Time, match string) bool {
+	if cna != "" && !strings.EqualFold(rep.Doc.Publisher.Name, cna) {
+		return false
+	}
+	if !since.IsZero() && rep.Doc.Tracking.InitialReleaseDate.Before(since) {
+		return false
+	}
+	if match != "" && !productTreeContains(rep.ProductTree, match) {
+		return false
+	}
+	return true
+}
+
+func productTreeContains(tree vulnrep.ProductTree, substr string) bool {
+	substr = strings.ToLower(substr)
+	for _, vendorBranch := range tree.Branches {
+		if strings.Contains(strings.ToLower(vendorBranch.Name), substr) {
+			return true
+		}
+		for _, productBranch := range vendorBranch.Branches {
+			if strings.Contains(strings.ToLower(productBranch.Name), substr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func defaultCVEListCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "vulnrepconv", "cvelist")
+	}
+	return filepath.Join(cacheDir, "vulnrepconv", "cvelist")
+}
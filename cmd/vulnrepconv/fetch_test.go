@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TIBCOSoftware/vulnrep"
+)
+
+func TestMatchesCriteria(t *testing.T) {
+	rep := vulnrep.Report{
+		Doc: vulnrep.Document{
+			Publisher: vulnrep.Publisher{Name: "example-cna"},
+			Tracking:  vulnrep.Tracking{InitialReleaseDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		ProductTree: vulnrep.ProductTree{
+			Branches: []vulnrep.Branch{
+				{Type: "Vendor", Name: "Example Vendor", Branches: []vulnrep.Branch{
+					{Type: "Product", Name: "ProductX"},
+				}},
+			},
+		},
+	}
+
+	cases := []struct {
+		name  string
+		cna   string
+		since string
+		match string
+		want  bool
+	}{
+		{name: "no filters", want: true},
+		{name: "cna match", cna: "example-cna", want: true},
+		{name: "cna case-insensitive", cna: "Example-CNA", want: true},
+		{name: "cna mismatch", cna: "other-cna", want: false},
+		{name: "since before", since: "2024-01-01", want: true},
+		{name: "since after", since: "2024-12-01", want: false},
+		{name: "match vendor", match: "vendor", want: true},
+		{name: "match product", match: "productx", want: true},
+		{name: "match miss", match: "nope", want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var since time.Time
+			if tc.since != "" {
+				var err error
+				since, err = time.Parse("2006-01-02", tc.since)
+				if err != nil {
+					t.Fatalf("time.Parse: %v", err)
+				}
+			}
+			got := matchesCriteria(rep, tc.cna, since, tc.match)
+			if got != tc.want {
+				t.Errorf("matchesCriteria() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProductTreeContains(t *testing.T) {
+	tree := vulnrep.ProductTree{
+		Branches: []vulnrep.Branch{
+			{Type: "Vendor", Name: "Example Vendor", Branches: []vulnrep.Branch{
+				{Type: "Product", Name: "ProductX"},
+			}},
+		},
+	}
+
+	if !productTreeContains(tree, "vendor") {
+		t.Error("expected substring match against vendor name")
+	}
+	if !productTreeContains(tree, "productx") {
+		t.Error("expected substring match against product name")
+	}
+	if productTreeContains(tree, "nonexistent") {
+		t.Error("expected no match for an unrelated substring")
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+// Usage: vulnrepconv scan [-module <path> | -binary <path>] [-output <fname>]
+//
+// The scan command runs govulncheck against a Go module or compiled binary and
+// synthesizes a CSAF 2.0 document from the vulnerabilities it finds: one
+// Vulnerability per OSV id, a ProductTree branch for each affected module@version
+// encountered in a call stack, and a Note summarizing each call stack. This gives
+// a one-shot way to turn "scan my project" into a compliance-ready advisory.
+//
+// Exactly one of -module or -binary must be given. If no output file is
+// indicated, the CSAF document is written to STDOUT.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/vuln/scan"
+
+	"github.com/TIBCOSoftware/vulnrep"
+)
+
+type toScan struct {
+	module       string
+	binary       string
+	output       string
+	help         bool
+	dontContinue bool
+}
+
+func (ts *toScan) parseArgs(appName string, args []string) error {
+
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	fs.StringVar(&ts.module, "module", "", "path to the Go module to scan")
+	fs.StringVar(&ts.binary, "binary", "", "path to a compiled Go binary to scan, instead of a module")
+	fs.StringVar(&ts.output, "output", "", "output destination for the generated CSAF document")
+	fs.BoolVar(&ts.help, "h", false, "set this flag for help.")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if ts.help {
+		fs.PrintDefaults()
+		ts.dontContinue = true
+		return nil
+	}
+	if ts.module == "" && ts.binary == "" {
+		return fmt.Errorf("must specify one of -module or -binary")
+	}
+	if ts.module != "" && ts.binary != "" {
+		return fmt.Errorf("-module and -binary are mutually exclusive")
+	}
+
+	return nil
+}
+
+func (ts *toScan) run(appName string, args []string) error {
+	err := ts.parseArgs(appName, args)
+	if err != nil {
+		return err
+	}
+
+	if ts.dontContinue {
+		return nil
+	}
+	return ts.doScan()
+}
+
+func (ts *toScan) doScan() (err error) {
+	msgs, err := runGovulncheck(ts.module, ts.binary)
+	if err != nil {
+		return err
+	}
+
+	rep := reportFromGovulncheck(msgs)
+
+	var out io.Writer
+	if ts.output == "" {
+		out = os.Stdout
+	} else {
+		outFile, err := os.Create(ts.output)
+		if err != nil {
+			return fmt.Errorf("unable to open output file for scan results: %v", err)
+		}
+		defer safeWriteClose(&err, outFile)
+		out = outFile
+	}
+
+	return rep.ToCSAF(out)
+}
+
+// runGovulncheck runs govulncheck -json against module or binary (exactly
+// one of which is non-empty) and returns the decoded stream of protocol
+// messages. It runs govulncheck in-process via golang.org/x/vuln/scan
+// rather than shelling out, so it works the same way regardless of which
+// govulncheck version is on the caller's PATH, if any.
+func runGovulncheck(module, binary string) ([]govulncheckMessage, error) {
+	var args []string
+	if binary != "" {
+		args = []string{"-mode=binary", "-json", binary}
+	} else {
+		args = []string{"-C", module, "-json", "./..."}
+	}
+
+	var buf bytes.Buffer
+	cmd := scan.Command(context.Background(), args...)
+	cmd.Stdout = &buf
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("running govulncheck: %w", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("running govulncheck: %w", err)
+	}
+
+	return decodeGovulncheckMessages(&buf)
+}
+
+// govulncheckMessage is one line of govulncheck's "-json" streaming output
+// protocol. Only the fields vulnrep needs to synthesize a report are
+// represented; see the golang.org/x/vuln/scan documentation for the rest.
+type govulncheckMessage struct {
+	OSV     json.RawMessage     `json:"osv,omitempty"`
+	Finding *govulncheckFinding `json:"finding,omitempty"`
+}
+
+type govulncheckFinding struct {
+	OSV   string             `json:"osv"`
+	Trace []govulncheckFrame `json:"trace,omitempty"`
+}
+
+type govulncheckFrame struct {
+	Module   string `json:"module,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Package  string `json:"package,omitempty"`
+	Function string `json:"function,omitempty"`
+}
+
+func decodeGovulncheckMessages(r io.Reader) ([]govulncheckMessage, error) {
+	var msgs []govulncheckMessage
+	dec := json.NewDecoder(r)
+	for {
+		var msg govulncheckMessage
+		err := dec.Decode(&msg)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding govulncheck output: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// reportFromGovulncheck turns the govulncheck protocol messages produced by
+// runGovulncheck into a Report: one Vulnerability per "osv" message - parsed
+// via vulnrep.ParseOSV, so vulnrep's OSV field mapping is the single source
+// of truth for that part of the translation - with ProductTree branches and
+// a call-stack Note added from the corresponding "finding" messages.
+func reportFromGovulncheck(msgs []govulncheckMessage) vulnrep.Report {
+	builder := vulnrep.NewBuilder(vulnrep.Document{
+		Title: "govulncheck scan results",
+		Type:  "security_advisory",
+	})
+
+	vulns := map[string]vulnrep.Vulnerability{}
+	var order []string
+	for _, msg := range msgs {
+		if len(msg.OSV) == 0 {
+			continue
+		}
+		osvRep, err := vulnrep.ParseOSV(bytes.NewReader(msg.OSV))
+		if err != nil || len(osvRep.Vulnerabilities) == 0 {
+			continue
+		}
+		vuln := osvRep.Vulnerabilities[0]
+		if _, seen := vulns[vuln.ID]; !seen {
+			order = append(order, vuln.ID)
+		}
+		vulns[vuln.ID] = vuln
+	}
+
+	for _, msg := range msgs {
+		if msg.Finding == nil {
+			continue
+		}
+		vuln, ok := vulns[msg.Finding.OSV]
+		if !ok {
+			continue
+		}
+
+		var frames []string
+		for _, frame := range msg.Finding.Trace {
+			if frame.Module == "" {
+				continue
+			}
+			productID := builder.AddProduct(frame.Module, frame.Package, frame.Version)
+			vuln.ProductStatuses = append(vuln.ProductStatuses, vulnrep.ProductStatus{
+				Type:      "known_affected",
+				ProductID: []string{productID},
+			})
+			frames = append(frames, fmt.Sprintf("%s@%s: %s", frame.Module, frame.Version, frame.Function))
+		}
+		if len(frames) > 0 {
+			vuln.Notes = append(vuln.Notes, vulnrep.Note{
+				Type: "other",
+				Text: "call stack: " + strings.Join(frames, " -> "),
+			})
+		}
+		vulns[msg.Finding.OSV] = vuln
+	}
+
+	for _, id := range order {
+		builder.AddVulnerability(vulns[id])
+	}
+	return builder.Report()
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReportFromGovulncheck(t *testing.T) {
+	osvEntry := `{"id":"GO-2024-0001","aliases":["CVE-2024-5555"],"summary":"example flaw"}`
+
+	msgs := []govulncheckMessage{
+		{OSV: json.RawMessage(osvEntry)},
+		{Finding: &govulncheckFinding{
+			OSV: "GO-2024-0001",
+			Trace: []govulncheckFrame{
+				{Module: "example.com/mod", Version: "v1.2.3", Package: "example.com/mod/pkg", Function: "Do"},
+			},
+		}},
+	}
+
+	rep := reportFromGovulncheck(msgs)
+
+	if len(rep.Vulnerabilities) != 1 {
+		t.Fatalf("got %d vulnerabilities, want 1", len(rep.Vulnerabilities))
+	}
+	vuln := rep.Vulnerabilities[0]
+	if vuln.ID != "GO-2024-0001" {
+		t.Errorf("ID = %q, want %q", vuln.ID, "GO-2024-0001")
+	}
+	if len(vuln.Notes) != 1 {
+		t.Fatalf("got %d notes, want 1 call-stack note", len(vuln.Notes))
+	}
+	if len(rep.ProductTree.Branches) != 1 {
+		t.Fatalf("got %d vendor branches, want 1", len(rep.ProductTree.Branches))
+	}
+}
@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+// Usage: vulnrepconv validate -input <fname> [-cvrf-schema <fname>] [-csaf-schema <fname>] [-format text|sarif]
+//
+// The validate command reads a CVRF or CSAF file and reports issues without
+// converting it. It runs two kinds of check:
+//
+//  1. Schema conformance: the document is validated against the CVRF 1.2 XSD
+//     (for XML input) or the CSAF 2.0 JSON Schema (for JSON input). Pass the
+//     schema's location with -cvrf-schema/-csaf-schema; if not given, schema
+//     validation is skipped - vulnrepconv does not bundle either schema - and
+//     a "schema-validation-skipped" warning diagnostic is emitted in its
+//     place, so the gap is visible in both -format text and -format sarif
+//     output rather than passing silently.
+//  2. Semantic checks vulnrep's parsers don't themselves enforce, including
+//     cross-referencing every ProductID and GroupID a Vulnerability names
+//     against the report's product_tree - see Report.Validate in the
+//     vulnrep package for the full list.
+//
+// Diagnostics are written as human-readable text by default, or as a SARIF
+// 2.1.0 JSON report with -format sarif, for CI systems that want to surface
+// issues inline. The command exits non-zero if any diagnostic has error
+// severity.
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/TIBCOSoftware/vulnrep"
+)
+
+type toValidate struct {
+	input        string
+	cvrfSchema   string
+	csafSchema   string
+	format       string
+	output       string
+	help         bool
+	dontContinue bool
+}
+
+func (tv *toValidate) parseArgs(appName string, args []string) error {
+
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	fs.StringVar(&tv.input, "input", "", "file to validate")
+	fs.StringVar(&tv.cvrfSchema, "cvrf-schema", "", "path to the CVRF 1.2 XSD, for schema validation of XML input")
+	fs.StringVar(&tv.csafSchema, "csaf-schema", "", "path to the CSAF 2.0 JSON Schema, for schema validation of JSON input")
+	fs.StringVar(&tv.format, "format", "text", "diagnostic output format: text or sarif")
+	fs.StringVar(&tv.output, "output", "", "output destination for diagnostics")
+	fs.BoolVar(&tv.help, "h", false, "set this flag for help.")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if tv.help {
+		fs.PrintDefaults()
+		tv.dontContinue = true
+		return nil
+	}
+	if tv.input == "" {
+		return fmt.Errorf("must specify a file to validate with the -input parameter")
+	}
+	if tv.format != "text" && tv.format != "sarif" {
+		return fmt.Errorf("unrecognized -format %q: must be text or sarif", tv.format)
+	}
+
+	return nil
+}
+
+func (tv *toValidate) run(appName string, args []string) error {
+	err := tv.parseArgs(appName, args)
+	if err != nil {
+		return err
+	}
+
+	if tv.dontContinue {
+		return nil
+	}
+	return tv.doValidate()
+}
+
+func (tv *toValidate) doValidate() (err error) {
+	raw, err := os.ReadFile(tv.input) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	var diags []vulnrep.Diagnostic
+	var rep vulnrep.Report
+
+	switch inputFormat(tv.input, formatAuto) {
+	case formatCVRF:
+		diags = append(diags, validateXMLSchema(raw, tv.cvrfSchema)...)
+		rep, err = vulnrep.ParseXML(bytesReader(raw))
+	case formatCSAF:
+		diags = append(diags, validateJSONSchema(raw, tv.csafSchema)...)
+		rep, err = vulnrep.ParseJSON(bytesReader(raw))
+	default:
+		return fmt.Errorf("validate only supports CVRF (.xml) and CSAF (.json) input, not %s", tv.input)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", tv.input, err)
+	}
+
+	diags = append(diags, rep.Validate()...)
+
+	var out io.Writer = os.Stdout
+	if tv.output != "" {
+		outFile, createErr := os.Create(tv.output)
+		if createErr != nil {
+			return fmt.Errorf("unable to open output file for diagnostics: %v", createErr)
+		}
+		defer safeWriteClose(&err, outFile)
+		out = outFile
+	}
+
+	if tv.format == "sarif" {
+		err = writeSARIF(out, tv.input, diags)
+	} else {
+		err = writeText(out, tv.input, diags)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, d := range diags {
+		if d.Severity == vulnrep.SeverityError {
+			return errValidationFailed
+		}
+	}
+	return nil
+}
+
+// errValidationFailed is returned by doValidate when at least one
+// error-severity diagnostic was found and already written to the output;
+// main reports it the same way as any other error.
+var errValidationFailed = errors.New("one or more error-severity diagnostics were found")
+
+func bytesReader(b []byte) io.Reader { return &byteReader{b: b} }
+
+// byteReader is a minimal io.Reader over an in-memory buffer, used instead
+// of bytes.NewReader so callers don't need a second import purely for
+// re-reading bytes already loaded for schema validation.
+type byteReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.b) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.b[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// validateXMLSchema validates raw against the CVRF 1.2 XSD at schemaPath by
+// shelling out to xmllint, if both are available. If schemaPath is empty or
+// xmllint is not on PATH, it returns a single warning diagnostic noting that
+// schema validation was skipped, rather than failing the whole command.
+func validateXMLSchema(raw []byte, schemaPath string) []vulnrep.Diagnostic {
+	if schemaPath == "" {
+		return []vulnrep.Diagnostic{skippedSchemaDiagnostic("-cvrf-schema was not given")}
+	}
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		return []vulnrep.Diagnostic{skippedSchemaDiagnostic("xmllint is not installed")}
+	}
+
+	if err := xml.Unmarshal(raw, new(struct{ XMLName xml.Name })); err != nil {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityError,
+			Rule:     "xml-well-formed",
+			Message:  err.Error(),
+		}}
+	}
+
+	cmd := exec.Command("xmllint", "--noout", "--schema", schemaPath, "-") //nolint:gosec
+	cmd.Stdin = bytesReader(raw)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityError,
+			Rule:     "cvrf-xsd",
+			Message:  string(out),
+		}}
+	}
+	return nil
+}
+
+// validateJSONSchema validates raw against the CSAF 2.0 JSON Schema at
+// schemaPath. If schemaPath is empty, it returns a single warning
+// diagnostic noting that schema validation was skipped, rather than
+// failing the whole command.
+func validateJSONSchema(raw []byte, schemaPath string) []vulnrep.Diagnostic {
+	if schemaPath == "" {
+		return []vulnrep.Diagnostic{skippedSchemaDiagnostic("-csaf-schema was not given")}
+	}
+
+	if !json.Valid(raw) {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityError,
+			Rule:     "json-well-formed",
+			Message:  "input is not well-formed JSON",
+		}}
+	}
+
+	schema, err := compileJSONSchema(schemaPath)
+	if err != nil {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityWarning,
+			Rule:     "csaf-json-schema",
+			Message:  fmt.Sprintf("could not load CSAF JSON Schema from %s: %v", schemaPath, err),
+		}}
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityError,
+			Rule:     "json-well-formed",
+			Message:  err.Error(),
+		}}
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		return []vulnrep.Diagnostic{{
+			Severity: vulnrep.SeverityError,
+			Rule:     "csaf-json-schema",
+			Message:  err.Error(),
+		}}
+	}
+	return nil
+}
+
+func compileJSONSchema(path string) (*jsonschema.Schema, error) {
+	return jsonschema.Compile(path)
+}
+
+func skippedSchemaDiagnostic(reason string) vulnrep.Diagnostic {
+	return vulnrep.Diagnostic{
+		Severity: vulnrep.SeverityWarning,
+		Rule:     "schema-validation-skipped",
+		Message:  "skipped schema validation: " + reason,
+	}
+}
+
+// The sarif family of types is the minimal subset of the SARIF 2.1.0 schema
+// needed to report vulnrep Diagnostics: one run, one rule per diagnostic
+// Rule, one result per Diagnostic.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	Snippet sarifMessage `json:"snippet"`
+}
+
+// sarifLevel maps a Diagnostic's Severity to the level SARIF expects.
+func sarifLevel(sev vulnrep.Severity) string {
+	if sev == vulnrep.SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+func writeSARIF(w io.Writer, fName string, diags []vulnrep.Diagnostic) error {
+	rules := map[string]bool{}
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{Name: "vulnrepconv"}},
+		}},
+	}
+
+	for _, d := range diags {
+		if !rules[d.Rule] {
+			rules[d.Rule] = true
+			log.Runs[0].Tool.Driver.Rules = append(log.Runs[0].Tool.Driver.Rules, sarifRule{ID: d.Rule})
+		}
+
+		result := sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: d.Message},
+		}
+		if d.Path != "" {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: fName},
+					Region:           &sarifRegion{Snippet: sarifMessage{Text: d.Path}},
+				},
+			}}
+		} else {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: fName}},
+			}}
+		}
+		log.Runs[0].Results = append(log.Runs[0].Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func writeText(w io.Writer, fName string, diags []vulnrep.Diagnostic) error {
+	if len(diags) == 0 {
+		_, err := fmt.Fprintf(w, "%s: no issues found\n", fName)
+		return err
+	}
+	for _, d := range diags {
+		loc := fName
+		if d.Path != "" {
+			loc = fmt.Sprintf("%s:%s", fName, d.Path)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: [%s] %s\n", loc, d.Severity, d.Rule, d.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
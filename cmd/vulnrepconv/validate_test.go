@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const cleanCVRF = `<?xml version="1.0" encoding="UTF-8"?>
+<cvrfdoc>
+  <DocumentTitle>Example Advisory</DocumentTitle>
+  <DocumentType>security_advisory</DocumentType>
+  <DocumentPublisher Type="Vendor">
+    <VendorName>Example Vendor</VendorName>
+  </DocumentPublisher>
+  <DocumentTracking>
+    <Identification><ID>EXAMPLE-2024-0001</ID></Identification>
+    <Status>Final</Status>
+    <Version>1.0.0</Version>
+  </DocumentTracking>
+  <ProductTree>
+    <FullProductName ProductID="CSAFPID-0001">ProductX</FullProductName>
+  </ProductTree>
+  <Vulnerability Ordinal="1">
+    <CVE>CVE-2024-0001</CVE>
+    <ProductStatuses>
+      <Status Type="Known Affected"><ProductID>CSAFPID-0001</ProductID></Status>
+    </ProductStatuses>
+  </Vulnerability>
+</cvrfdoc>
+`
+
+const badCVRF = `<?xml version="1.0" encoding="UTF-8"?>
+<cvrfdoc>
+  <DocumentTitle>Example Advisory</DocumentTitle>
+  <DocumentType>security_advisory</DocumentType>
+  <DocumentPublisher Type="Vendor">
+    <VendorName>Example Vendor</VendorName>
+  </DocumentPublisher>
+  <DocumentTracking>
+    <Identification><ID>EXAMPLE-2024-0001</ID></Identification>
+    <Status>Final</Status>
+    <Version>1.0.0</Version>
+  </DocumentTracking>
+  <ProductTree>
+    <FullProductName ProductID="CSAFPID-0001">ProductX</FullProductName>
+  </ProductTree>
+  <Vulnerability Ordinal="1">
+    <CVE>CVE-2024-0001</CVE>
+    <ProductStatuses>
+      <Status Type="Known Affected"><ProductID>CSAFPID-9999</ProductID></Status>
+    </ProductStatuses>
+  </Vulnerability>
+</cvrfdoc>
+`
+
+func writeInput(t *testing.T, contents string) string {
+	t.Helper()
+	fName := filepath.Join(t.TempDir(), "report.xml")
+	if err := os.WriteFile(fName, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return fName
+}
+
+func TestDoValidateClean(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "out.txt")
+	tv := toValidate{input: writeInput(t, cleanCVRF), format: "text", output: outFile}
+	if err := tv.doValidate(); err != nil {
+		t.Fatalf("doValidate: %v", err)
+	}
+
+	got, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	// No -cvrf-schema was given, so schema validation is skipped with a
+	// warning; the semantic checks themselves should find nothing to flag.
+	if !bytes.Contains(got, []byte("schema-validation-skipped")) {
+		t.Errorf("output = %q, want a schema-validation-skipped warning", got)
+	}
+	if bytes.Contains(got, []byte("error")) {
+		t.Errorf("output = %q, want no error-severity diagnostics", got)
+	}
+}
+
+func TestDoValidateErrorSeverityExitsNonZero(t *testing.T) {
+	tv := toValidate{
+		input:  writeInput(t, badCVRF),
+		format: "text",
+		output: filepath.Join(t.TempDir(), "out.txt"),
+	}
+
+	err := tv.doValidate()
+	if !errors.Is(err, errValidationFailed) {
+		t.Fatalf("doValidate error = %v, want errValidationFailed", err)
+	}
+}
+
+func TestDoValidateSARIFShape(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "out.sarif")
+	tv := toValidate{
+		input:  writeInput(t, badCVRF),
+		format: "sarif",
+		output: outFile,
+	}
+
+	if err := tv.doValidate(); !errors.Is(err, errValidationFailed) {
+		t.Fatalf("doValidate error = %v, want errValidationFailed", err)
+	}
+
+	raw, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(raw, &log); err != nil {
+		t.Fatalf("unmarshaling SARIF output: %v", err)
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("Version = %q, want 2.1.0", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("len(Runs) = %d, want 1", len(log.Runs))
+	}
+	if log.Runs[0].Tool.Driver.Name != "vulnrepconv" {
+		t.Errorf("Driver.Name = %q, want vulnrepconv", log.Runs[0].Tool.Driver.Name)
+	}
+	if len(log.Runs[0].Results) == 0 {
+		t.Fatal("Results is empty, want at least one result for the undeclared ProductID")
+	}
+	found := false
+	for _, r := range log.Runs[0].Results {
+		if r.RuleID == "product-reference" {
+			found = true
+			if r.Level != "error" {
+				t.Errorf("product-reference result level = %q, want error", r.Level)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no product-reference result in %+v", log.Runs[0].Results)
+	}
+}
+
+func TestWriteTextNoIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeText(&buf, "report.xml", nil); err != nil {
+		t.Fatalf("writeText: %v", err)
+	}
+	if buf.String() != "report.xml: no issues found\n" {
+		t.Errorf("writeText output = %q", buf.String())
+	}
+}
@@ -3,32 +3,18 @@
 // Copyright 2019, TIBCO Software Inc. This file is subject to the license
 // terms contained in the license file that is distributed with this file.
 
-// Usage: vulnrepconv -input <fname> [-output <fname>]
+// Usage: vulnrepconv <command> [arguments]
 //
-// The tool vulnrepconv converts vulnerability reports to/from CVRF(xml) and CSAF(json)
-// formats. Note that there are some features of the JSON format not available in the XML
-// format (notably, translation support), so this tool cannot fully convert a document
-// from JSON --> XML --> JSON, but it will get about as close as possible. Round
-// trips from XML --> JSON --> XML will work. See the Limitations section for details.
+// The tool vulnrepconv works with vulnerability reports in CVRF(xml), CSAF(json),
+// CVE Record Format v5.0 (json) and OSV schema 1.x (json) formats. It supports the
+// following commands:
 //
-// The input file is required. If no output file is indicated, then the output is
-// written to STDOUT.
+//	convert     convert a report between formats
+//	scan        generate a CSAF report from govulncheck scan results
+//	fetch       merge matching advisories from a CVEProject/cvelist mirror
+//	validate    check a report against its schema and vulnrep's semantic rules
 //
-// The type of file is assumed based on file extension. Input and output files
-// must have either a ".xml" or a ".json" extension.
-//
-// Limitations
-//
-// Round trip conversions from XML-->JSON-->XML work, but may not be identical, due
-// to a number of factors:
-// - namespace prefixes might change
-// - whitespaces might change
-// - attribute ordering might change
-// - xml:lang attributes will be discarded
-// - CDATA and comments may change
-//
-// A carefully constructed XML file, however, will be able to successfully round-trip
-// back to a byte-for-byte identical file.
+// Run "vulnrepconv <command> -h" for the flags a given command accepts.
 //
 // See Also
 //
@@ -37,120 +23,57 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
-
-	"github.com/TIBCOSoftware/vulnrep"
+	"sort"
+	"strings"
 )
 
-type toConvert struct {
-	input        string
-	output       string
-	help         bool
-	dontContinue bool
-}
-
-func (tc *toConvert) parseArgs(appName string, args []string) error {
-
-	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
-	fs.StringVar(&tc.input, "input", "", "file to parse for conversion")
-	fs.StringVar(&tc.output, "output", "", "output destination")
-	fs.BoolVar(&tc.help, "h", false, "set this flag for help.")
-
-	err := fs.Parse(args)
-	if err != nil {
-		return err
-	}
-	if tc.help {
-		fs.PrintDefaults()
-		tc.dontContinue = true
-		return nil
-	}
-	if tc.input == "" {
-		return fmt.Errorf("must specify an input file with the -input parameter")
-	}
-
-	return nil
-}
-
-func parseXMLFile(fName string) (vulnrep.Report, error) {
-	// no security risk to parsing XML here.
-	f, err := os.Open(fName) //nolint:gosec
-	if err != nil {
-		return vulnrep.Report{}, err
-	}
-	defer safeClose(f)
-
-	return vulnrep.ParseXML(f)
-
+// subcommand is implemented by each of vulnrepconv's commands.
+type subcommand interface {
+	run(appName string, args []string) error
 }
-func parseJSONFile(fName string) (vulnrep.Report, error) {
-	// no security risk to parsing JSON here.
-	f, err := os.Open(fName) //nolint:gosec
-	if err != nil {
-		return vulnrep.Report{}, err
-	}
-	defer safeClose(f)
-
-	return vulnrep.ParseJSON(f)
 
+// commands maps each vulnrepconv command name to the subcommand that
+// implements it.
+var commands = map[string]func() subcommand{
+	"convert":  func() subcommand { return &toConvert{} },
+	"scan":     func() subcommand { return &toScan{} },
+	"fetch":    func() subcommand { return &toFetch{} },
+	"validate": func() subcommand { return &toValidate{} },
 }
 
-func (tc *toConvert) doConversion() (err error) {
-
-	var readFunc func(string) (vulnrep.Report, error)
-
-	ext := filepath.Ext(tc.input)
-	switch ext {
-	case ".xml":
-		readFunc = parseXMLFile
-	case ".json":
-		readFunc = parseJSONFile
-	default:
-		return fmt.Errorf("unrecognized file extension %v - don't know how to parse file", ext)
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s <command> [arguments]\nCommands: %s\n", os.Args[0], commandNames())
+		os.Exit(1)
 	}
 
-	rep, err := readFunc(tc.input)
-	if err != nil {
-		return err
+	newCmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unrecognized command %q. Commands: %s\n", os.Args[1], commandNames())
+		os.Exit(1)
 	}
+	cmd := newCmd()
 
-	outFunc := rep.ToCSAF
-	var out io.Writer
-	if tc.output == "" {
-		out = os.Stdout
-	} else {
-		outFile, err := os.Create(tc.output)
-		if err != nil {
-			return fmt.Errorf("unable to open output file for conversion: %v", err)
-		}
-		defer safeWriteClose(&err, outFile)
-		out = outFile
-
-		switch filepath.Ext(tc.output) {
-		case ".xml":
-			outFunc = rep.ToCVRF
-		default:
-			outFunc = rep.ToCSAF
-		}
+	appName := os.Args[0] + " " + os.Args[1]
+	if err := cmd.run(appName, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
-
-	return outFunc(out)
+	os.Exit(0)
 }
 
-func (tc *toConvert) run(appName string, args []string) error {
-	err := tc.parseArgs(appName, args)
-	if err != nil {
-		return err
+// commandNames returns vulnrepconv's command names, sorted, as a
+// comma-separated string for use in usage messages.
+func commandNames() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
-
-	if tc.dontContinue {
-		return nil
-	}
-	return tc.doConversion()
+	sort.Strings(names)
+	return strings.Join(names, ", ")
 }
 
 func safeClose(rc io.Closer) {
@@ -169,14 +92,3 @@ func safeWriteClose(err *error, wc io.Closer) {
 		*err = newErr
 	}
 }
-
-func main() {
-
-	app := &toConvert{}
-	err := app.run(os.Args[0], os.Args[1:])
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
-	os.Exit(0)
-}
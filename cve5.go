@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseCVE5 reads a CVE Record Format v5.0 JSON document - the format
+// published by the CVE Project (see golang.org/x/vulndb's cveschema5
+// package for the canonical schema) - producing a Report.
+//
+// Only the cna container is consulted; ADP containers, if present, are
+// ignored. Because a CVE 5 record describes exactly one CVE, the resulting
+// Report always has a single Vulnerability.
+func ParseCVE5(r io.Reader) (Report, error) {
+	var rec cve5Record
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return Report{}, fmt.Errorf("vulnrep: parsing CVE 5: %w", err)
+	}
+	return rec.toReport(), nil
+}
+
+// ToCVE5 writes rep to w as a CVE Record Format v5.0 JSON document.
+//
+// Only the first Vulnerability in rep is represented; a CVRF or CSAF
+// document containing more than one Vulnerability cannot be captured in a
+// single CVE Record, since a record's CVE ID describes just one
+// vulnerability. Callers converting a multi-vulnerability Report should
+// call ToCVE5 once per Vulnerability.
+func (rep Report) ToCVE5(w io.Writer) error {
+	rec, err := fromReportToCVE5(rep)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rec); err != nil {
+		return fmt.Errorf("vulnrep: writing CVE 5: %w", err)
+	}
+	return nil
+}
+
+// The cve5 family of types is a minimal subset of the CVE Record Format
+// v5.0 JSON schema - just enough to round-trip the data vulnrep already
+// models (title, descriptions, affected products, references and CVSS
+// v3.1 metrics). Fields outside that subset (e.g. ADP containers, impact
+// types, credits) are not represented.
+
+type cve5Record struct {
+	DataType    string         `json:"dataType"`
+	DataVersion string         `json:"dataVersion"`
+	CVEMetadata cve5Metadata   `json:"cveMetadata"`
+	Containers  cve5Containers `json:"containers"`
+}
+
+type cve5Metadata struct {
+	CVEID             string    `json:"cveId"`
+	State             string    `json:"state"`
+	AssignerShortName string    `json:"assignerShortName,omitempty"`
+	DatePublished     time.Time `json:"datePublished,omitempty"`
+}
+
+type cve5Containers struct {
+	CNA cve5CNAContainer `json:"cna"`
+}
+
+type cve5CNAContainer struct {
+	Title        string            `json:"title,omitempty"`
+	Descriptions []cve5Description `json:"descriptions,omitempty"`
+	Affected     []cve5Affected    `json:"affected,omitempty"`
+	References   []cve5Reference   `json:"references,omitempty"`
+	Metrics      []cve5Metric      `json:"metrics,omitempty"`
+}
+
+type cve5Description struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type cve5Affected struct {
+	Vendor   string        `json:"vendor,omitempty"`
+	Product  string        `json:"product,omitempty"`
+	Versions []cve5Version `json:"versions,omitempty"`
+}
+
+type cve5Version struct {
+	Version     string `json:"version"`
+	Status      string `json:"status"`
+	LessThan    string `json:"lessThan,omitempty"`
+	VersionType string `json:"versionType,omitempty"`
+}
+
+type cve5Reference struct {
+	URL  string   `json:"url"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+type cve5Metric struct {
+	CVSSV3_1 *cve5CVSSData `json:"cvssV3_1,omitempty"`
+}
+
+type cve5CVSSData struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+}
+
+func (rec cve5Record) toReport() Report {
+	cna := rec.Containers.CNA
+
+	rep := Report{
+		Doc: Document{
+			Title:     cna.Title,
+			Publisher: Publisher{Name: rec.CVEMetadata.AssignerShortName},
+			Tracking: Tracking{
+				ID:                 rec.CVEMetadata.CVEID,
+				Status:             rec.CVEMetadata.State,
+				InitialReleaseDate: rec.CVEMetadata.DatePublished,
+			},
+		},
+	}
+
+	vuln := Vulnerability{
+		Title: cna.Title,
+		ID:    rec.CVEMetadata.CVEID,
+		CVE:   rec.CVEMetadata.CVEID,
+	}
+	for _, d := range cna.Descriptions {
+		vuln.Notes = append(vuln.Notes, Note{Type: "description", Audience: d.Lang, Text: d.Value})
+	}
+	for _, r := range cna.References {
+		tag := ""
+		if len(r.Tags) > 0 {
+			tag = r.Tags[0]
+		}
+		vuln.References = append(vuln.References, Reference{URL: r.URL, Type: tag})
+	}
+	for _, m := range cna.Metrics {
+		if m.CVSSV3_1 == nil {
+			continue
+		}
+		vuln.CVSSScoreSets = append(vuln.CVSSScoreSets, ScoreSet{
+			Version:   m.CVSSV3_1.Version,
+			BaseScore: m.CVSSV3_1.BaseScore,
+			Vector:    m.CVSSV3_1.VectorString,
+		})
+	}
+
+	for _, aff := range cna.Affected {
+		productID := fmt.Sprintf("%s:%s", aff.Vendor, aff.Product)
+		prod := Branch{
+			Type: "Product",
+			Name: aff.Product,
+			Product: &FullProductName{
+				Name:      aff.Product,
+				ProductID: productID,
+			},
+		}
+		for _, v := range aff.Versions {
+			prod.Branches = append(prod.Branches, versionBranch(v, productID))
+			vuln.ProductStatuses = append(vuln.ProductStatuses, ProductStatus{Type: v.Status, ProductID: []string{productID}})
+		}
+		rep.ProductTree.Branches = append(rep.ProductTree.Branches, Branch{
+			Type:     "Vendor",
+			Name:     aff.Vendor,
+			Branches: []Branch{prod},
+		})
+	}
+
+	rep.Vulnerabilities = []Vulnerability{vuln}
+	return rep
+}
+
+// versionBranch represents a single CVE 5 affected version (or version
+// range, when LessThan is set) as a ProductTree branch leaf.
+func versionBranch(v cve5Version, productID string) Branch {
+	name := v.Version
+	if v.LessThan != "" {
+		name = fmt.Sprintf("%s - %s", v.Version, v.LessThan)
+	}
+	return Branch{
+		Type: "Version",
+		Name: name,
+		Product: &FullProductName{
+			Name:      name,
+			ProductID: productID,
+		},
+	}
+}
+
+func fromReportToCVE5(rep Report) (cve5Record, error) {
+	if len(rep.Vulnerabilities) == 0 {
+		return cve5Record{}, fmt.Errorf("vulnrep: report has no vulnerabilities to convert to CVE 5")
+	}
+	vuln := rep.Vulnerabilities[0]
+
+	rec := cve5Record{
+		DataType:    "CVE_RECORD",
+		DataVersion: "5.0",
+		CVEMetadata: cve5Metadata{
+			CVEID:             vuln.CVE,
+			State:             "PUBLISHED",
+			AssignerShortName: rep.Doc.Publisher.Name,
+			DatePublished:     rep.Doc.Tracking.InitialReleaseDate,
+		},
+	}
+	if rec.CVEMetadata.CVEID == "" {
+		rec.CVEMetadata.CVEID = vuln.ID
+	}
+
+	cna := cve5CNAContainer{Title: vuln.Title}
+	for _, n := range vuln.Notes {
+		cna.Descriptions = append(cna.Descriptions, cve5Description{Lang: defaultString(n.Audience, "en"), Value: n.Text})
+	}
+	for _, r := range vuln.References {
+		ref := cve5Reference{URL: r.URL}
+		if r.Type != "" {
+			ref.Tags = []string{r.Type}
+		}
+		cna.References = append(cna.References, ref)
+	}
+	for _, ss := range vuln.CVSSScoreSets {
+		cna.Metrics = append(cna.Metrics, cve5Metric{
+			CVSSV3_1: &cve5CVSSData{Version: defaultString(ss.Version, "3.1"), VectorString: ss.Vector, BaseScore: ss.BaseScore},
+		})
+	}
+
+	cna.Affected = affectedFromProductTree(rep.ProductTree)
+
+	rec.Containers = cve5Containers{CNA: cna}
+	return rec, nil
+}
+
+// affectedFromProductTree walks a two-level Vendor/Product ProductTree (the
+// shape produced by toReport above) back into CVE 5 affected entries,
+// recovering the per-version data from each product branch's own Version
+// sub-branches (the shape versionBranch produces). Trees with a different
+// shape are represented on a best-effort basis: any branch with a Product
+// leaf but no Version sub-branches becomes one affected entry with a single
+// version taken from the product name.
+func affectedFromProductTree(pt ProductTree) []cve5Affected {
+	var out []cve5Affected
+	for _, vendorBranch := range pt.Branches {
+		for _, productBranch := range vendorBranch.Branches {
+			if productBranch.Product == nil {
+				continue
+			}
+			versions := versionsFromBranches(productBranch.Branches)
+			if len(versions) == 0 {
+				versions = []cve5Version{{Version: productBranch.Product.Name, Status: "affected"}}
+			}
+			out = append(out, cve5Affected{
+				Vendor:   vendorBranch.Name,
+				Product:  productBranch.Name,
+				Versions: versions,
+			})
+		}
+	}
+	return out
+}
+
+// versionsFromBranches turns a product's Version sub-branches (produced by
+// versionBranch, with names like "1.2.0" or "1.2.0 - 1.3.0") back into CVE 5
+// affected versions.
+func versionsFromBranches(branches []Branch) []cve5Version {
+	var out []cve5Version
+	for _, vb := range branches {
+		version, lessThan := vb.Name, ""
+		if idx := strings.Index(vb.Name, " - "); idx >= 0 {
+			version, lessThan = vb.Name[:idx], vb.Name[idx+3:]
+		}
+		out = append(out, cve5Version{Version: version, Status: "affected", LessThan: lessThan})
+	}
+	return out
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
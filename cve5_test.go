@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCVE5RoundTrip(t *testing.T) {
+	rep := Report{
+		Doc: Document{
+			Title:     "Example Vendor ProductX Buffer Overflow",
+			Publisher: Publisher{Name: "example"},
+			Tracking:  Tracking{ID: "CVE-2024-12345", Status: "PUBLISHED"},
+		},
+		Vulnerabilities: []Vulnerability{
+			{
+				Title: "Example Vendor ProductX Buffer Overflow",
+				ID:    "CVE-2024-12345",
+				CVE:   "CVE-2024-12345",
+				Notes: []Note{{Type: "description", Audience: "en", Text: "A buffer overflow was found."}},
+				References: []Reference{
+					{URL: "https://example.com/advisory", Type: "Advisory"},
+				},
+				ProductStatuses: []ProductStatus{
+					{Type: "affected", ProductID: []string{"example:productx"}},
+				},
+			},
+		},
+		ProductTree: ProductTree{
+			Branches: []Branch{
+				{
+					Type: "Vendor",
+					Name: "example",
+					Branches: []Branch{
+						{
+							Type: "Product",
+							Name: "productx",
+							Product: &FullProductName{
+								Name:      "productx",
+								ProductID: "example:productx",
+							},
+							Branches: []Branch{
+								{
+									Type: "Version",
+									Name: "1.0.0",
+									Product: &FullProductName{
+										Name:      "1.0.0",
+										ProductID: "example:productx",
+									},
+								},
+								{
+									Type: "Version",
+									Name: "1.1.0 - 1.2.0",
+									Product: &FullProductName{
+										Name:      "1.1.0 - 1.2.0",
+										ProductID: "example:productx",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rep.ToCVE5(&buf); err != nil {
+		t.Fatalf("ToCVE5: %v", err)
+	}
+
+	got, err := ParseCVE5(&buf)
+	if err != nil {
+		t.Fatalf("ParseCVE5: %v", err)
+	}
+
+	if len(got.Vulnerabilities) != 1 {
+		t.Fatalf("got %d vulnerabilities, want 1", len(got.Vulnerabilities))
+	}
+
+	wantAffected := []cve5Affected{
+		{
+			Vendor:  "example",
+			Product: "productx",
+			Versions: []cve5Version{
+				{Version: "1.0.0", Status: "affected"},
+				{Version: "1.1.0", Status: "affected", LessThan: "1.2.0"},
+			},
+		},
+	}
+	gotAffected := affectedFromProductTree(rep.ProductTree)
+	if !reflect.DeepEqual(gotAffected, wantAffected) {
+		t.Errorf("affectedFromProductTree() = %+v, want %+v", gotAffected, wantAffected)
+	}
+
+	gotVendor := got.ProductTree.Branches[0]
+	if gotVendor.Name != "example" {
+		t.Errorf("got vendor %q, want %q", gotVendor.Name, "example")
+	}
+	gotProduct := gotVendor.Branches[0]
+	if len(gotProduct.Branches) != 2 {
+		t.Fatalf("got %d version branches, want 2", len(gotProduct.Branches))
+	}
+	if got, want := gotProduct.Branches[0].Name, "1.0.0"; got != want {
+		t.Errorf("version[0].Name = %q, want %q", got, want)
+	}
+	if got, want := gotProduct.Branches[1].Name, "1.1.0 - 1.2.0"; got != want {
+		t.Errorf("version[1].Name = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+// Package vulnrep implements an in-memory representation of a vulnerability
+// report, along with readers and writers for the formats in which such
+// reports are commonly published:
+//
+//   - CVRF 1.2, an XML format defined by ICASI / FIRST.
+//   - CSAF 2.0, the JSON successor to CVRF, defined by OASIS.
+//   - CVE Record Format v5.0, the JSON format published by the CVE Project.
+//   - OSV, the JSON schema 1.x format used by the OSS-Fuzz/OSV ecosystem.
+//
+// A Report is produced by ParseXML, ParseJSON, ParseCVE5 or ParseOSV, and can
+// be serialized back out with Report.ToCVRF, Report.ToCSAF, Report.ToCVE5 or
+// Report.ToOSV. Because CSAF carries a handful of fields CVRF has no room for
+// (translations, in particular), a JSON->XML->JSON round trip is not
+// guaranteed to be lossless. An XML->JSON->XML round trip keeps nearly
+// everything, but acknowledgment URLs are dropped and vulnerability ordinals
+// come back numbered from 0. CVE 5 and OSV describe a single vulnerability
+// per document: Report.ToCVE5 writes only the first Vulnerability of a
+// multi-vulnerability Report, while Report.ToOSV writes one NDJSON line per
+// Vulnerability.
+//
+// A Builder merges several Reports that describe different vulnerabilities
+// of the same product line into a single Report, deduplicating repeated
+// product tree branches as it goes. Report.Validate checks a Report against
+// vulnrep's own semantic rules (ProductID and GroupID cross-references,
+// revision history order, the security_advisory profile, CVSS score range
+// and vector version); it does not check conformance against the CVRF/CSAF
+// schemas themselves.
+package vulnrep
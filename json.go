@@ -0,0 +1,482 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ParseJSON reads a CSAF 2.0 document from r, producing a Report.
+func ParseJSON(r io.Reader) (Report, error) {
+	var doc csafDoc
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Report{}, fmt.Errorf("vulnrep: parsing CSAF: %w", err)
+	}
+	return doc.toReport(), nil
+}
+
+// ToCSAF writes rep to w as a CSAF 2.0 JSON document.
+func (rep Report) ToCSAF(w io.Writer) error {
+	doc := fromReportToCSAF(rep)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("vulnrep: writing CSAF: %w", err)
+	}
+	return nil
+}
+
+// The csafDoc family of types is the wire representation of a CSAF 2.0
+// document - the shape dictated by the CSAF JSON schema, as opposed to
+// Report, which is the shape convenient for callers.
+
+type csafDoc struct {
+	Document        csafDocument        `json:"document"`
+	ProductTree     csafProductTree     `json:"product_tree,omitempty"`
+	Vulnerabilities []csafVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type csafDocument struct {
+	Title             string          `json:"title"`
+	Category          string          `json:"category"`
+	Publisher         csafPublisher   `json:"publisher"`
+	Tracking          csafTracking    `json:"tracking"`
+	Notes             []csafNote      `json:"notes,omitempty"`
+	References        []csafReference `json:"references,omitempty"`
+	Acknowledgments   []csafAck       `json:"acknowledgments,omitempty"`
+	AggregateSeverity string          `json:"aggregate_severity,omitempty"`
+	Distribution      string          `json:"distribution,omitempty"`
+}
+
+type csafPublisher struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type csafTracking struct {
+	ID                 string         `json:"id"`
+	Status             string         `json:"status"`
+	Version            string         `json:"version"`
+	RevisionHistory    []csafRevision `json:"revision_history,omitempty"`
+	InitialReleaseDate time.Time      `json:"initial_release_date"`
+	CurrentReleaseDate time.Time      `json:"current_release_date"`
+}
+
+type csafRevision struct {
+	Number  string    `json:"number"`
+	Date    time.Time `json:"date"`
+	Summary string    `json:"summary"`
+}
+
+type csafNote struct {
+	Title    string `json:"title,omitempty"`
+	Category string `json:"category"`
+	Audience string `json:"audience,omitempty"`
+	Text     string `json:"text"`
+}
+
+type csafReference struct {
+	URL      string `json:"url"`
+	Summary  string `json:"summary"`
+	Category string `json:"category,omitempty"`
+}
+
+type csafAck struct {
+	Names        []string `json:"names,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	URLs         []string `json:"urls,omitempty"`
+}
+
+type csafProductTree struct {
+	Branches         []csafBranch          `json:"branches,omitempty"`
+	FullProductNames []csafFullProductName `json:"full_product_names,omitempty"`
+	Relationships    []csafRelationship    `json:"relationships,omitempty"`
+	Groups           []csafGroup           `json:"product_groups,omitempty"`
+}
+
+type csafBranch struct {
+	Category string               `json:"category"`
+	Name     string               `json:"name"`
+	Branches []csafBranch         `json:"branches,omitempty"`
+	Product  *csafFullProductName `json:"product,omitempty"`
+}
+
+type csafFullProductName struct {
+	Name                        string               `json:"name"`
+	ProductID                   string               `json:"product_id"`
+	ProductIdentificationHelper *csafProductIDHelper `json:"product_identification_helper,omitempty"`
+}
+
+type csafProductIDHelper struct {
+	CPE string `json:"cpe,omitempty"`
+}
+
+type csafRelationship struct {
+	ProductReference          string              `json:"product_reference"`
+	Category                  string              `json:"category"`
+	RelatesToProductReference string              `json:"relates_to_product_reference"`
+	FullProductName           csafFullProductName `json:"full_product_name"`
+}
+
+type csafGroup struct {
+	GroupID    string   `json:"group_id"`
+	Summary    string   `json:"summary,omitempty"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+type csafVulnerability struct {
+	Title           string              `json:"title,omitempty"`
+	CVE             string              `json:"cve,omitempty"`
+	CWE             *csafCWE            `json:"cwe,omitempty"`
+	DiscoveryDate   *time.Time          `json:"discovery_date,omitempty"`
+	ReleaseDate     *time.Time          `json:"release_date,omitempty"`
+	IDs             []csafID            `json:"ids,omitempty"`
+	Notes           []csafNote          `json:"notes,omitempty"`
+	References      []csafReference     `json:"references,omitempty"`
+	Acknowledgments []csafAck           `json:"acknowledgments,omitempty"`
+	ProductStatus   map[string][]string `json:"product_status,omitempty"`
+	Remediations    []csafRemediation   `json:"remediations,omitempty"`
+	Threats         []csafThreat        `json:"threats,omitempty"`
+	Involvements    []csafInvolvement   `json:"involvements,omitempty"`
+	Scores          []csafScore         `json:"scores,omitempty"`
+}
+
+type csafID struct {
+	SystemName string `json:"system_name"`
+	Text       string `json:"text"`
+}
+
+type csafCWE struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type csafRemediation struct {
+	Category   string   `json:"category"`
+	Details    string   `json:"details"`
+	URL        string   `json:"url,omitempty"`
+	ProductIDs []string `json:"product_ids,omitempty"`
+	GroupIDs   []string `json:"group_ids,omitempty"`
+}
+
+type csafThreat struct {
+	Category   string     `json:"category"`
+	Details    string     `json:"details"`
+	Date       *time.Time `json:"date,omitempty"`
+	ProductIDs []string   `json:"product_ids,omitempty"`
+	GroupIDs   []string   `json:"group_ids,omitempty"`
+}
+
+type csafInvolvement struct {
+	Party   string `json:"party"`
+	Status  string `json:"status"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type csafScore struct {
+	CVSSV3     *csafCVSSV3 `json:"cvss_v3,omitempty"`
+	ProductIDs []string    `json:"products,omitempty"`
+}
+
+type csafCVSSV3 struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+}
+
+func (doc csafDoc) toReport() Report {
+	rep := Report{
+		Doc: Document{
+			Title: doc.Document.Title,
+			Type:  doc.Document.Category,
+			Publisher: Publisher{
+				Name:      doc.Document.Publisher.Name,
+				Namespace: doc.Document.Publisher.Namespace,
+				Type:      doc.Document.Publisher.Category,
+			},
+			Tracking: Tracking{
+				ID:                 doc.Document.Tracking.ID,
+				Status:             doc.Document.Tracking.Status,
+				Version:            doc.Document.Tracking.Version,
+				InitialReleaseDate: doc.Document.Tracking.InitialReleaseDate,
+				CurrentReleaseDate: doc.Document.Tracking.CurrentReleaseDate,
+			},
+			AggregateSeverity: doc.Document.AggregateSeverity,
+			Distribution:      doc.Document.Distribution,
+		},
+	}
+	for _, rev := range doc.Document.Tracking.RevisionHistory {
+		rep.Doc.Tracking.RevisionHistory = append(rep.Doc.Tracking.RevisionHistory, Revision{
+			Number: rev.Number, Date: rev.Date, Description: rev.Summary,
+		})
+	}
+	for _, n := range doc.Document.Notes {
+		rep.Doc.Notes = append(rep.Doc.Notes, Note{Title: n.Title, Type: n.Category, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range doc.Document.References {
+		rep.Doc.References = append(rep.Doc.References, Reference{URL: r.URL, Description: r.Summary, Type: r.Category})
+	}
+	for _, a := range doc.Document.Acknowledgments {
+		rep.Doc.Acknowledgments = append(rep.Doc.Acknowledgments, Acknowledgment{
+			Names: a.Names, Organization: a.Organization, Description: a.Summary,
+		})
+	}
+
+	rep.ProductTree = fromCSAFProductTree(doc.ProductTree)
+
+	for i, v := range doc.Vulnerabilities {
+		rep.Vulnerabilities = append(rep.Vulnerabilities, fromCSAFVulnerability(i, v))
+	}
+	return rep
+}
+
+func fromCSAFProductTree(pt csafProductTree) ProductTree {
+	out := ProductTree{}
+	for _, b := range pt.Branches {
+		out.Branches = append(out.Branches, fromCSAFBranch(b))
+	}
+	for _, fpn := range pt.FullProductNames {
+		out.FullProductNames = append(out.FullProductNames, fromCSAFFullProductName(fpn))
+	}
+	for _, rel := range pt.Relationships {
+		out.Relationships = append(out.Relationships, Relationship{
+			ProductReference:          rel.ProductReference,
+			RelationType:              rel.Category,
+			RelatesToProductReference: rel.RelatesToProductReference,
+			ProductID:                 rel.FullProductName.ProductID,
+			Name:                      rel.FullProductName.Name,
+		})
+	}
+	for _, g := range pt.Groups {
+		out.Groups = append(out.Groups, Group{GroupID: g.GroupID, Description: g.Summary, ProductID: g.ProductIDs})
+	}
+	return out
+}
+
+func fromCSAFBranch(b csafBranch) Branch {
+	out := Branch{Type: b.Category, Name: b.Name}
+	for _, sub := range b.Branches {
+		out.Branches = append(out.Branches, fromCSAFBranch(sub))
+	}
+	if b.Product != nil {
+		p := fromCSAFFullProductName(*b.Product)
+		out.Product = &p
+	}
+	return out
+}
+
+func fromCSAFFullProductName(fpn csafFullProductName) FullProductName {
+	out := FullProductName{Name: fpn.Name, ProductID: fpn.ProductID}
+	if fpn.ProductIdentificationHelper != nil {
+		out.CPE = fpn.ProductIdentificationHelper.CPE
+	}
+	return out
+}
+
+func fromCSAFVulnerability(ordinal int, v csafVulnerability) Vulnerability {
+	out := Vulnerability{
+		Ordinal: ordinal,
+		Title:   v.Title,
+		CVE:     v.CVE,
+	}
+	for _, id := range v.IDs {
+		if id.SystemName == "CVE" || out.ID == "" {
+			out.ID = id.Text
+		}
+	}
+	if v.CWE != nil {
+		out.CWE = CWE{ID: v.CWE.ID, Name: v.CWE.Name}
+	}
+	if v.DiscoveryDate != nil {
+		out.DiscoveryDate = *v.DiscoveryDate
+	}
+	if v.ReleaseDate != nil {
+		out.ReleaseDate = *v.ReleaseDate
+	}
+	for _, n := range v.Notes {
+		out.Notes = append(out.Notes, Note{Title: n.Title, Type: n.Category, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range v.References {
+		out.References = append(out.References, Reference{URL: r.URL, Description: r.Summary, Type: r.Category})
+	}
+	for _, a := range v.Acknowledgments {
+		out.Acknowledgments = append(out.Acknowledgments, Acknowledgment{Names: a.Names, Organization: a.Organization, Description: a.Summary})
+	}
+	for typ, ids := range v.ProductStatus {
+		out.ProductStatuses = append(out.ProductStatuses, ProductStatus{Type: typ, ProductID: ids})
+	}
+	for _, rem := range v.Remediations {
+		out.Remediations = append(out.Remediations, Remediation{
+			Type: rem.Category, Description: rem.Details, URL: rem.URL, ProductID: rem.ProductIDs, GroupID: rem.GroupIDs,
+		})
+	}
+	for _, t := range v.Threats {
+		th := Threat{Type: t.Category, Description: t.Details, ProductID: t.ProductIDs, GroupID: t.GroupIDs}
+		if t.Date != nil {
+			th.Date = *t.Date
+		}
+		out.Threats = append(out.Threats, th)
+	}
+	for _, inv := range v.Involvements {
+		out.Involvements = append(out.Involvements, Involvement{Party: inv.Party, Status: inv.Status, Description: inv.Summary})
+	}
+	for _, s := range v.Scores {
+		if s.CVSSV3 == nil {
+			continue
+		}
+		out.CVSSScoreSets = append(out.CVSSScoreSets, ScoreSet{
+			Version: s.CVSSV3.Version, BaseScore: s.CVSSV3.BaseScore, Vector: s.CVSSV3.VectorString, ProductID: s.ProductIDs,
+		})
+	}
+	return out
+}
+
+func fromReportToCSAF(rep Report) csafDoc {
+	doc := csafDoc{
+		Document: csafDocument{
+			Title:    rep.Doc.Title,
+			Category: rep.Doc.Type,
+			Publisher: csafPublisher{
+				Category:  rep.Doc.Publisher.Type,
+				Name:      rep.Doc.Publisher.Name,
+				Namespace: rep.Doc.Publisher.Namespace,
+			},
+			Tracking: csafTracking{
+				ID:                 rep.Doc.Tracking.ID,
+				Status:             rep.Doc.Tracking.Status,
+				Version:            rep.Doc.Tracking.Version,
+				InitialReleaseDate: rep.Doc.Tracking.InitialReleaseDate,
+				CurrentReleaseDate: rep.Doc.Tracking.CurrentReleaseDate,
+			},
+			AggregateSeverity: rep.Doc.AggregateSeverity,
+			Distribution:      rep.Doc.Distribution,
+		},
+	}
+	for _, rev := range rep.Doc.Tracking.RevisionHistory {
+		doc.Document.Tracking.RevisionHistory = append(doc.Document.Tracking.RevisionHistory, csafRevision{
+			Number: rev.Number, Date: rev.Date, Summary: rev.Description,
+		})
+	}
+	for _, n := range rep.Doc.Notes {
+		doc.Document.Notes = append(doc.Document.Notes, csafNote{Title: n.Title, Category: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range rep.Doc.References {
+		doc.Document.References = append(doc.Document.References, csafReference{URL: r.URL, Summary: r.Description, Category: r.Type})
+	}
+	for _, a := range rep.Doc.Acknowledgments {
+		doc.Document.Acknowledgments = append(doc.Document.Acknowledgments, csafAck{Names: a.Names, Organization: a.Organization, Summary: a.Description})
+	}
+
+	doc.ProductTree = toCSAFProductTree(rep.ProductTree)
+
+	for _, v := range rep.Vulnerabilities {
+		doc.Vulnerabilities = append(doc.Vulnerabilities, toCSAFVulnerability(v))
+	}
+	return doc
+}
+
+func toCSAFProductTree(pt ProductTree) csafProductTree {
+	out := csafProductTree{}
+	for _, b := range pt.Branches {
+		out.Branches = append(out.Branches, toCSAFBranch(b))
+	}
+	for _, fpn := range pt.FullProductNames {
+		out.FullProductNames = append(out.FullProductNames, toCSAFFullProductName(fpn))
+	}
+	for _, rel := range pt.Relationships {
+		out.Relationships = append(out.Relationships, csafRelationship{
+			ProductReference:          rel.ProductReference,
+			Category:                  rel.RelationType,
+			RelatesToProductReference: rel.RelatesToProductReference,
+			FullProductName:           csafFullProductName{Name: rel.Name, ProductID: rel.ProductID},
+		})
+	}
+	for _, g := range pt.Groups {
+		out.Groups = append(out.Groups, csafGroup{GroupID: g.GroupID, Summary: g.Description, ProductIDs: g.ProductID})
+	}
+	return out
+}
+
+func toCSAFBranch(b Branch) csafBranch {
+	out := csafBranch{Category: b.Type, Name: b.Name}
+	for _, sub := range b.Branches {
+		out.Branches = append(out.Branches, toCSAFBranch(sub))
+	}
+	if b.Product != nil {
+		fpn := toCSAFFullProductName(*b.Product)
+		out.Product = &fpn
+	}
+	return out
+}
+
+func toCSAFFullProductName(fpn FullProductName) csafFullProductName {
+	out := csafFullProductName{Name: fpn.Name, ProductID: fpn.ProductID}
+	if fpn.CPE != "" {
+		out.ProductIdentificationHelper = &csafProductIDHelper{CPE: fpn.CPE}
+	}
+	return out
+}
+
+func toCSAFVulnerability(v Vulnerability) csafVulnerability {
+	out := csafVulnerability{
+		Title: v.Title,
+		CVE:   v.CVE,
+	}
+	if v.ID != "" {
+		out.IDs = append(out.IDs, csafID{SystemName: "CVE", Text: v.ID})
+	}
+	if v.CWE.ID != "" {
+		out.CWE = &csafCWE{ID: v.CWE.ID, Name: v.CWE.Name}
+	}
+	if !v.DiscoveryDate.IsZero() {
+		out.DiscoveryDate = &v.DiscoveryDate
+	}
+	if !v.ReleaseDate.IsZero() {
+		out.ReleaseDate = &v.ReleaseDate
+	}
+	for _, n := range v.Notes {
+		out.Notes = append(out.Notes, csafNote{Title: n.Title, Category: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range v.References {
+		out.References = append(out.References, csafReference{URL: r.URL, Summary: r.Description, Category: r.Type})
+	}
+	for _, a := range v.Acknowledgments {
+		out.Acknowledgments = append(out.Acknowledgments, csafAck{Names: a.Names, Organization: a.Organization, Summary: a.Description})
+	}
+	if len(v.ProductStatuses) > 0 {
+		out.ProductStatus = map[string][]string{}
+		for _, s := range v.ProductStatuses {
+			out.ProductStatus[s.Type] = s.ProductID
+		}
+	}
+	for _, rem := range v.Remediations {
+		out.Remediations = append(out.Remediations, csafRemediation{
+			Category: rem.Type, Details: rem.Description, URL: rem.URL, ProductIDs: rem.ProductID, GroupIDs: rem.GroupID,
+		})
+	}
+	for _, t := range v.Threats {
+		ct := csafThreat{Category: t.Type, Details: t.Description, ProductIDs: t.ProductID, GroupIDs: t.GroupID}
+		if !t.Date.IsZero() {
+			ct.Date = &t.Date
+		}
+		out.Threats = append(out.Threats, ct)
+	}
+	for _, inv := range v.Involvements {
+		out.Involvements = append(out.Involvements, csafInvolvement{Party: inv.Party, Status: inv.Status, Summary: inv.Description})
+	}
+	for _, ss := range v.CVSSScoreSets {
+		out.Scores = append(out.Scores, csafScore{
+			CVSSV3:     &csafCVSSV3{Version: ss.Version, VectorString: ss.Vector, BaseScore: ss.BaseScore},
+			ProductIDs: ss.ProductID,
+		})
+	}
+	return out
+}
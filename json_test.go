@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONFixture(t *testing.T) {
+	f, err := os.Open("testdata/report.csaf.json")
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	rep, err := ParseJSON(f)
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+
+	if rep.Doc.Title != "Example Vendor Security Advisory" {
+		t.Errorf("Doc.Title = %q", rep.Doc.Title)
+	}
+	if rep.Doc.Tracking.ID != "EXAMPLE-2024-0001" {
+		t.Errorf("Doc.Tracking.ID = %q", rep.Doc.Tracking.ID)
+	}
+	if len(rep.ProductTree.Groups) != 1 || rep.ProductTree.Groups[0].GroupID != "CSAFGID-0001" {
+		t.Fatalf("ProductTree.Groups = %+v", rep.ProductTree.Groups)
+	}
+	if len(rep.Vulnerabilities) != 1 {
+		t.Fatalf("len(Vulnerabilities) = %d, want 1", len(rep.Vulnerabilities))
+	}
+	v := rep.Vulnerabilities[0]
+	if v.CVE != "CVE-2024-0001" || v.ID != "CVE-2024-0001" {
+		t.Errorf("Vulnerability CVE/ID = %q/%q", v.CVE, v.ID)
+	}
+	if len(v.ProductStatuses) != 1 || v.ProductStatuses[0].Type != "Known Affected" {
+		t.Errorf("Vulnerability.ProductStatuses = %+v", v.ProductStatuses)
+	}
+	if len(v.CVSSScoreSets) != 1 || v.CVSSScoreSets[0].Vector == "" {
+		t.Errorf("Vulnerability.CVSSScoreSets = %+v", v.CVSSScoreSets)
+	}
+
+	if diags := rep.Validate(); len(diags) != 0 {
+		t.Errorf("Validate() = %+v, want none", diags)
+	}
+}
+
+func TestCSAFRoundTrip(t *testing.T) {
+	want := sampleReport()
+
+	var buf bytes.Buffer
+	if err := want.ToCSAF(&buf); err != nil {
+		t.Fatalf("ToCSAF: %v", err)
+	}
+
+	got, err := ParseJSON(&buf)
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("round trip through CSAF changed the report:\n got:  %+v\n want: %+v", got, want)
+	}
+}
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseOSV reads a single OSV (Open Source Vulnerability) schema 1.x JSON
+// entry from r, producing a Report with exactly one Vulnerability.
+//
+// To parse a report containing several OSV entries (an NDJSON stream, or a
+// directory of entry files), callers should call ParseOSV once per entry
+// and merge the resulting Reports' Vulnerabilities and ProductTree.
+func ParseOSV(r io.Reader) (Report, error) {
+	var entry osvEntry
+	if err := json.NewDecoder(r).Decode(&entry); err != nil {
+		return Report{}, fmt.Errorf("vulnrep: parsing OSV: %w", err)
+	}
+	return entry.toReport(), nil
+}
+
+// ToOSV writes rep to w as OSV JSON. Because a CVRF/CSAF Report may
+// contain more than one vulnerability while an OSV document describes
+// exactly one, ToOSV writes an NDJSON stream - one OSV entry per line, in
+// the order the vulnerabilities appear in rep. A Report with a single
+// Vulnerability therefore produces a single line of JSON.
+func (rep Report) ToOSV(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, v := range rep.Vulnerabilities {
+		entry := fromVulnerabilityToOSV(rep.ProductTree, v)
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("vulnrep: writing OSV: %w", err)
+		}
+	}
+	return nil
+}
+
+// The osv family of types is a minimal subset of the OSV schema 1.x JSON
+// format - enough to round-trip the data vulnrep already models (summary,
+// details, references with typed kinds, and affected ranges).
+
+type osvEntry struct {
+	ID         string         `json:"id"`
+	Summary    string         `json:"summary,omitempty"`
+	Details    string         `json:"details,omitempty"`
+	Published  string         `json:"published,omitempty"`
+	Modified   string         `json:"modified,omitempty"`
+	Aliases    []string       `json:"aliases,omitempty"`
+	References []osvReference `json:"references,omitempty"`
+	Affected   []osvAffected  `json:"affected,omitempty"`
+}
+
+type osvReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type osvAffected struct {
+	Package osvPackage `json:"package"`
+	Ranges  []osvRange `json:"ranges,omitempty"`
+}
+
+type osvPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+}
+
+// osvReferenceType maps a CVRF/CSAF reference Type to the closest OSV
+// reference type. CVRF reference types are free text, so this is a
+// best-effort mapping; unrecognized types fall back to "WEB".
+func osvReferenceType(cvrfType string) string {
+	switch cvrfType {
+	case "Advisory", "External":
+		return "ADVISORY"
+	case "Patch", "Fix":
+		return "FIX"
+	case "Report", "Issue":
+		return "REPORT"
+	case "Package":
+		return "PACKAGE"
+	default:
+		return "WEB"
+	}
+}
+
+// cvrfReferenceType maps an OSV reference type to a CVRF/CSAF reference
+// Type, used when building a Report's References from parsed OSV data. It
+// reverses osvReferenceType for ADVISORY, FIX, REPORT and PACKAGE; every
+// other OSV type, WEB included, becomes "External", which osvReferenceType
+// maps to ADVISORY.
+func cvrfReferenceType(osvType string) string {
+	switch osvType {
+	case "ADVISORY":
+		return "Advisory"
+	case "FIX":
+		return "Patch"
+	case "REPORT":
+		return "Report"
+	case "PACKAGE":
+		return "Package"
+	default:
+		return "External"
+	}
+}
+
+func (entry osvEntry) toReport() Report {
+	rep := Report{
+		Doc: Document{
+			Tracking: Tracking{ID: entry.ID},
+		},
+	}
+
+	vuln := Vulnerability{
+		ID:  entry.ID,
+		CVE: firstCVEAlias(entry),
+	}
+	if entry.Summary != "" {
+		vuln.Title = entry.Summary
+	}
+	if entry.Details != "" {
+		vuln.Notes = append(vuln.Notes, Note{Type: "details", Text: entry.Details})
+	}
+	for _, r := range entry.References {
+		vuln.References = append(vuln.References, Reference{URL: r.URL, Type: cvrfReferenceType(r.Type)})
+	}
+
+	for _, aff := range entry.Affected {
+		productID := fmt.Sprintf("%s:%s", aff.Package.Ecosystem, aff.Package.Name)
+		product := Branch{
+			Type: "Product",
+			Name: aff.Package.Name,
+			Product: &FullProductName{
+				Name:      aff.Package.Name,
+				ProductID: productID,
+			},
+		}
+		for _, rng := range aff.Ranges {
+			for _, ev := range rng.Events {
+				if ev.Introduced == "" && ev.Fixed == "" {
+					continue
+				}
+				name := ev.Introduced
+				if ev.Fixed != "" {
+					name = fmt.Sprintf("%s - %s", ev.Introduced, ev.Fixed)
+				}
+				product.Branches = append(product.Branches, Branch{
+					Type: "Version",
+					Name: name,
+					Product: &FullProductName{
+						Name:      name,
+						ProductID: productID,
+					},
+				})
+			}
+		}
+		rep.ProductTree.Branches = append(rep.ProductTree.Branches, Branch{
+			Type:     "Vendor",
+			Name:     aff.Package.Ecosystem,
+			Branches: []Branch{product},
+		})
+		vuln.ProductStatuses = append(vuln.ProductStatuses, ProductStatus{Type: "known_affected", ProductID: []string{productID}})
+	}
+
+	rep.Vulnerabilities = []Vulnerability{vuln}
+	return rep
+}
+
+func firstCVEAlias(entry osvEntry) string {
+	if strings.HasPrefix(entry.ID, "CVE-") {
+		return entry.ID
+	}
+	for _, a := range entry.Aliases {
+		if strings.HasPrefix(a, "CVE-") {
+			return a
+		}
+	}
+	return ""
+}
+
+func fromVulnerabilityToOSV(tree ProductTree, v Vulnerability) osvEntry {
+	id := v.ID
+	if id == "" {
+		id = v.CVE
+	}
+	entry := osvEntry{
+		ID:      id,
+		Summary: v.Title,
+	}
+	if v.CVE != "" && v.CVE != id {
+		entry.Aliases = append(entry.Aliases, v.CVE)
+	}
+	for _, n := range v.Notes {
+		if entry.Details == "" {
+			entry.Details = n.Text
+		}
+	}
+	for _, r := range v.References {
+		entry.References = append(entry.References, osvReference{Type: osvReferenceType(r.Type), URL: r.URL})
+	}
+
+	entry.Affected = affectedFromBranches(tree, v)
+	return entry
+}
+
+// affectedFromBranches walks a two-level Vendor/Product ProductTree (the
+// shape ParseCVE5/ParseOSV produce) into OSV affected packages, restricting
+// to the products referenced by v's ProductStatuses.
+func affectedFromBranches(tree ProductTree, v Vulnerability) []osvAffected {
+	wanted := map[string]bool{}
+	for _, ps := range v.ProductStatuses {
+		for _, id := range ps.ProductID {
+			wanted[id] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	var out []osvAffected
+	for _, vendorBranch := range tree.Branches {
+		for _, productBranch := range vendorBranch.Branches {
+			if productBranch.Product == nil || !wanted[productBranch.Product.ProductID] {
+				continue
+			}
+			affected := osvAffected{
+				Package: osvPackage{Ecosystem: vendorBranch.Name, Name: productBranch.Name},
+			}
+			if len(productBranch.Branches) > 0 {
+				affected.Ranges = []osvRange{{Type: "ECOSYSTEM", Events: versionEvents(productBranch.Branches)}}
+			}
+			out = append(out, affected)
+		}
+	}
+	return out
+}
+
+// versionEvents turns a product's Version sub-branches (produced by
+// ParseOSV/ParseCVE5 with names like "1.2.0" or "1.2.0 - 1.3.0") back into
+// OSV range events.
+func versionEvents(versionBranches []Branch) []osvEvent {
+	var events []osvEvent
+	for _, vb := range versionBranches {
+		introduced, fixed := vb.Name, ""
+		if idx := strings.Index(vb.Name, " - "); idx >= 0 {
+			introduced, fixed = vb.Name[:idx], vb.Name[idx+3:]
+		}
+		events = append(events, osvEvent{Introduced: introduced, Fixed: fixed})
+	}
+	return events
+}
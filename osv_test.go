@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOSVRoundTrip(t *testing.T) {
+	rep := Report{
+		Vulnerabilities: []Vulnerability{
+			{
+				ID:    "GO-2024-0001",
+				CVE:   "CVE-2024-4444",
+				Title: "example flaw",
+				Notes: []Note{{Type: "details", Text: "details text"}},
+				References: []Reference{
+					{URL: "https://example.com/fix", Type: "Patch"},
+				},
+				ProductStatuses: []ProductStatus{
+					{Type: "known_affected", ProductID: []string{"Go:example.com/mod"}},
+				},
+			},
+		},
+		ProductTree: ProductTree{
+			Branches: []Branch{
+				{
+					Type: "Vendor",
+					Name: "Go",
+					Branches: []Branch{
+						{
+							Type: "Product",
+							Name: "example.com/mod",
+							Product: &FullProductName{
+								Name:      "example.com/mod",
+								ProductID: "Go:example.com/mod",
+							},
+							Branches: []Branch{
+								{
+									Type: "Version",
+									Name: "1.0.0 - 1.1.0",
+									Product: &FullProductName{
+										Name:      "1.0.0 - 1.1.0",
+										ProductID: "Go:example.com/mod",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rep.ToOSV(&buf); err != nil {
+		t.Fatalf("ToOSV: %v", err)
+	}
+
+	got, err := ParseOSV(&buf)
+	if err != nil {
+		t.Fatalf("ParseOSV: %v", err)
+	}
+	if len(got.Vulnerabilities) != 1 {
+		t.Fatalf("got %d vulnerabilities, want 1", len(got.Vulnerabilities))
+	}
+	gotVuln := got.Vulnerabilities[0]
+	if gotVuln.CVE != "CVE-2024-4444" {
+		t.Errorf("CVE = %q, want %q", gotVuln.CVE, "CVE-2024-4444")
+	}
+	if gotVuln.Title != "example flaw" {
+		t.Errorf("Title = %q, want %q", gotVuln.Title, "example flaw")
+	}
+
+	gotProduct := got.ProductTree.Branches[0].Branches[0]
+	if len(gotProduct.Branches) != 1 || gotProduct.Branches[0].Name != "1.0.0 - 1.1.0" {
+		t.Errorf("version branches = %+v, want a single \"1.0.0 - 1.1.0\" branch", gotProduct.Branches)
+	}
+}
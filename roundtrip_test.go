@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCVRFToCSAFRoundTrip carries a report through CVRF and back, then
+// through CSAF and back, checking that nothing a future struct-tag change
+// in xml.go or json.go might silently drop trips Validate.
+func TestCVRFToCSAFRoundTrip(t *testing.T) {
+	rep := sampleReport()
+
+	var xmlBuf bytes.Buffer
+	if err := rep.ToCVRF(&xmlBuf); err != nil {
+		t.Fatalf("ToCVRF: %v", err)
+	}
+	cvrfRep, err := ParseXML(&xmlBuf)
+	if err != nil {
+		t.Fatalf("ParseXML: %v", err)
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := cvrfRep.ToCSAF(&jsonBuf); err != nil {
+		t.Fatalf("ToCSAF: %v", err)
+	}
+	csafRep, err := ParseJSON(&jsonBuf)
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+
+	if diags := csafRep.Validate(); len(diags) != 0 {
+		t.Fatalf("Validate() after CVRF->CSAF round trip = %+v, want none", diags)
+	}
+
+	if csafRep.Doc.Title != rep.Doc.Title {
+		t.Errorf("Doc.Title = %q, want %q", csafRep.Doc.Title, rep.Doc.Title)
+	}
+	if csafRep.Doc.Tracking.ID != rep.Doc.Tracking.ID {
+		t.Errorf("Doc.Tracking.ID = %q, want %q", csafRep.Doc.Tracking.ID, rep.Doc.Tracking.ID)
+	}
+	if len(csafRep.Vulnerabilities) != 1 || csafRep.Vulnerabilities[0].CVE != rep.Vulnerabilities[0].CVE {
+		t.Errorf("Vulnerabilities = %+v, want CVE %q", csafRep.Vulnerabilities, rep.Vulnerabilities[0].CVE)
+	}
+	if len(csafRep.ProductTree.Groups) != 1 || csafRep.ProductTree.Groups[0].GroupID != rep.ProductTree.Groups[0].GroupID {
+		t.Errorf("ProductTree.Groups = %+v", csafRep.ProductTree.Groups)
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import "time"
+
+// Report is the in-memory representation of a vulnerability report - the
+// parsed contents of either a CVRF document or a CSAF document. The two
+// formats describe the same information, so a single Report can be
+// serialized back out as either one.
+type Report struct {
+	Doc             Document
+	ProductTree     ProductTree
+	Vulnerabilities []Vulnerability
+}
+
+// Document carries the metadata of a report: who published it, what it is
+// called, and how its revisions are tracked.
+type Document struct {
+	Title             string
+	Type              string
+	Publisher         Publisher
+	Tracking          Tracking
+	Notes             []Note
+	References        []Reference
+	Acknowledgments   []Acknowledgment
+	AggregateSeverity string
+	Distribution      string
+}
+
+// Publisher identifies who produced the report.
+type Publisher struct {
+	Name      string
+	Namespace string
+	Type      string
+}
+
+// Tracking carries the revision and status metadata for a Document.
+type Tracking struct {
+	ID                 string
+	Status             string
+	Version            string
+	RevisionHistory    []Revision
+	InitialReleaseDate time.Time
+	CurrentReleaseDate time.Time
+}
+
+// Revision is a single entry in a Tracking's RevisionHistory.
+type Revision struct {
+	Number      string
+	Date        time.Time
+	Description string
+}
+
+// ProductTree describes the set of products a report can refer to, either
+// directly (FullProductNames), through a hierarchy of Branches, or as a
+// named Group of ProductIDs.
+type ProductTree struct {
+	Branches         []Branch
+	FullProductNames []FullProductName
+	Relationships    []Relationship
+	Groups           []Group
+}
+
+// Branch is one node of a ProductTree hierarchy (e.g. Vendor -> Product ->
+// Version). A leaf Branch carries a Product.
+type Branch struct {
+	Type     string
+	Name     string
+	Branches []Branch
+	Product  *FullProductName
+}
+
+// FullProductName names a single product, optionally with a CPE or other
+// identifier, and assigns it the ProductID referenced elsewhere in the
+// report (e.g. from Vulnerability.ProductStatuses).
+type FullProductName struct {
+	Name      string
+	ProductID string
+	CPE       string
+}
+
+// Relationship records that one product (ProductReference) relates to
+// another (RelatesToProductReference) in some way, yielding a new product ID.
+type Relationship struct {
+	ProductReference          string
+	RelationType              string
+	RelatesToProductReference string
+	ProductID                 string
+	Name                      string
+}
+
+// Group is a named collection of ProductIDs that a Vulnerability's
+// Remediations or Threats may reference by GroupID instead of enumerating
+// the products individually.
+type Group struct {
+	GroupID     string
+	Description string
+	ProductID   []string
+}
+
+// Vulnerability is a single vulnerability entry in a report. A CVRF/CSAF
+// document may contain more than one of these.
+type Vulnerability struct {
+	Ordinal         int
+	Title           string
+	ID              string
+	CVE             string
+	CWE             CWE
+	DiscoveryDate   time.Time
+	ReleaseDate     time.Time
+	Notes           []Note
+	References      []Reference
+	Acknowledgments []Acknowledgment
+	ProductStatuses []ProductStatus
+	Remediations    []Remediation
+	Threats         []Threat
+	Involvements    []Involvement
+	CVSSScoreSets   []ScoreSet
+}
+
+// CWE identifies a weakness class from the Common Weakness Enumeration.
+type CWE struct {
+	ID   string
+	Name string
+}
+
+// Note is free text attached to a Document or Vulnerability, such as a
+// summary, description, or details note.
+type Note struct {
+	Title    string
+	Type     string
+	Audience string
+	Text     string
+}
+
+// Reference is a link to supporting material - an advisory, a patch, a
+// vendor bulletin.
+type Reference struct {
+	URL         string
+	Description string
+	Type        string
+}
+
+// Acknowledgment credits an individual or organization for a report.
+type Acknowledgment struct {
+	Names        []string
+	Organization string
+	Description  string
+	URL          string
+}
+
+// ProductStatus records the disposition (known affected, fixed, ...) of a
+// set of products (identified by ProductID) with respect to a Vulnerability.
+type ProductStatus struct {
+	Type      string
+	ProductID []string
+}
+
+// Remediation describes a fix or mitigation available for a Vulnerability.
+type Remediation struct {
+	Type        string
+	Description string
+	URL         string
+	ProductID   []string
+	GroupID     []string
+}
+
+// Threat describes an active exploitation or impact claim for a
+// Vulnerability.
+type Threat struct {
+	Type        string
+	Description string
+	Date        time.Time
+	ProductID   []string
+	GroupID     []string
+}
+
+// Involvement records a party's involvement in the handling of a
+// Vulnerability (e.g. a vendor who was contacted).
+type Involvement struct {
+	Party       string
+	Status      string
+	Description string
+}
+
+// ScoreSet is a CVSS score, along with the vector it was computed from, for
+// a set of products.
+type ScoreSet struct {
+	Version   string
+	BaseScore float64
+	Vector    string
+	ProductID []string
+}
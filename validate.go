@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a Diagnostic produced by Report.Validate.
+type Severity string
+
+// The severities a Diagnostic can carry. Error is returned for findings
+// that violate a hard requirement of the format; Warning is used for
+// findings that are very likely problems but that Validate cannot fully
+// confirm (e.g. a CVSS vector that names a different version than its
+// ScoreSet).
+const (
+	SeverityError   = Severity("error")
+	SeverityWarning = Severity("warning")
+)
+
+// Diagnostic is one issue found by Report.Validate: a semantic check the
+// CVRF/CSAF parsers themselves don't enforce, as opposed to schema
+// (XSD/JSON Schema) conformance, which callers must check separately
+// against the raw document bytes.
+type Diagnostic struct {
+	Severity Severity
+	Rule     string
+	Path     string
+	Message  string
+}
+
+// Validate runs vulnrep's semantic checks against rep and returns every
+// issue found. An empty result means rep passed every check Validate knows
+// about; it does not mean rep is a valid CVRF or CSAF document, since
+// Validate does not check rep against either format's schema.
+func (rep Report) Validate() []Diagnostic {
+	var diags []Diagnostic
+	diags = append(diags, checkProductReferences(rep)...)
+	diags = append(diags, checkRevisionHistory(rep)...)
+	diags = append(diags, checkCSAFProfile(rep)...)
+	diags = append(diags, checkCVSSConsistency(rep)...)
+	return diags
+}
+
+// knownProductIDs collects every ProductID declared anywhere in rep's
+// ProductTree: on FullProductNames, on branch leaves, and as the ID a
+// Relationship assigns to a combined product.
+func knownProductIDs(tree ProductTree) map[string]bool {
+	ids := map[string]bool{}
+	for _, fpn := range tree.FullProductNames {
+		ids[fpn.ProductID] = true
+	}
+	for _, rel := range tree.Relationships {
+		ids[rel.ProductID] = true
+	}
+	var walk func([]Branch)
+	walk = func(branches []Branch) {
+		for _, b := range branches {
+			if b.Product != nil {
+				ids[b.Product.ProductID] = true
+			}
+			walk(b.Branches)
+		}
+	}
+	walk(tree.Branches)
+	return ids
+}
+
+// knownGroupIDs collects every GroupID declared in rep's ProductTree.Groups.
+func knownGroupIDs(tree ProductTree) map[string]bool {
+	ids := map[string]bool{}
+	for _, g := range tree.Groups {
+		ids[g.GroupID] = true
+	}
+	return ids
+}
+
+// checkProductReferences verifies that every ProductID and GroupID a
+// Vulnerability refers to (in its ProductStatuses, Remediations, Threats
+// and CVSSScoreSets) is declared somewhere in the report's ProductTree -
+// ProductID against FullProductNames/Branches/Relationships, GroupID
+// against ProductTree.Groups.
+func checkProductReferences(rep Report) []Diagnostic {
+	knownProducts := knownProductIDs(rep.ProductTree)
+	knownGroups := knownGroupIDs(rep.ProductTree)
+
+	var diags []Diagnostic
+	checkProduct := func(path, productID string) {
+		if productID != "" && !knownProducts[productID] {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Rule:     "product-reference",
+				Path:     path,
+				Message:  fmt.Sprintf("references ProductID %q, which is not declared in product_tree", productID),
+			})
+		}
+	}
+	checkGroup := func(path, groupID string) {
+		if groupID != "" && !knownGroups[groupID] {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Rule:     "group-reference",
+				Path:     path,
+				Message:  fmt.Sprintf("references GroupID %q, which is not declared in product_tree's product_groups", groupID),
+			})
+		}
+	}
+
+	for vi, v := range rep.Vulnerabilities {
+		base := fmt.Sprintf("vulnerabilities[%d]", vi)
+		for si, ps := range v.ProductStatuses {
+			for pi, id := range ps.ProductID {
+				checkProduct(fmt.Sprintf("%s.productStatuses[%d].productID[%d]", base, si, pi), id)
+			}
+		}
+		for ri, rem := range v.Remediations {
+			for pi, id := range rem.ProductID {
+				checkProduct(fmt.Sprintf("%s.remediations[%d].productID[%d]", base, ri, pi), id)
+			}
+			for gi, id := range rem.GroupID {
+				checkGroup(fmt.Sprintf("%s.remediations[%d].groupID[%d]", base, ri, gi), id)
+			}
+		}
+		for ti, t := range v.Threats {
+			for pi, id := range t.ProductID {
+				checkProduct(fmt.Sprintf("%s.threats[%d].productID[%d]", base, ti, pi), id)
+			}
+			for gi, id := range t.GroupID {
+				checkGroup(fmt.Sprintf("%s.threats[%d].groupID[%d]", base, ti, gi), id)
+			}
+		}
+		for si, ss := range v.CVSSScoreSets {
+			for pi, id := range ss.ProductID {
+				checkProduct(fmt.Sprintf("%s.cvssScoreSets[%d].productID[%d]", base, si, pi), id)
+			}
+		}
+	}
+	return diags
+}
+
+// checkRevisionHistory verifies that a Document's RevisionHistory is
+// non-decreasing (by dotted numeric version) and that its last entry
+// matches Tracking.Version.
+func checkRevisionHistory(rep Report) []Diagnostic {
+	revs := rep.Doc.Tracking.RevisionHistory
+	if len(revs) == 0 {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for i := 1; i < len(revs); i++ {
+		if compareVersions(revs[i-1].Number, revs[i].Number) > 0 {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Rule:     "revision-history-monotonic",
+				Path:     fmt.Sprintf("document.tracking.revisionHistory[%d]", i),
+				Message:  fmt.Sprintf("revision %q is not >= preceding revision %q", revs[i].Number, revs[i-1].Number),
+			})
+		}
+	}
+
+	last := revs[len(revs)-1].Number
+	if rep.Doc.Tracking.Version != "" && last != rep.Doc.Tracking.Version {
+		diags = append(diags, Diagnostic{
+			Severity: SeverityError,
+			Rule:     "revision-history-matches-version",
+			Path:     "document.tracking.version",
+			Message:  fmt.Sprintf("tracking.version %q does not match the last revision history entry %q", rep.Doc.Tracking.Version, last),
+		})
+	}
+	return diags
+}
+
+// compareVersions compares two dotted-numeric version strings (e.g.
+// "1.2.0"), returning -1, 0 or 1. Non-numeric components compare as equal
+// to avoid false positives on version schemes vulnrep doesn't understand.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			continue
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkCSAFProfile verifies the requirements of the CSAF "security_advisory"
+// profile that vulnrep's parsers don't themselves enforce: at least one
+// Vulnerability must be present.
+func checkCSAFProfile(rep Report) []Diagnostic {
+	if rep.Doc.Type != "security_advisory" {
+		return nil
+	}
+	if len(rep.Vulnerabilities) == 0 {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Rule:     "csaf-security-advisory-profile",
+			Path:     "vulnerabilities",
+			Message:  "document.category is security_advisory, which requires at least one vulnerability",
+		}}
+	}
+	return nil
+}
+
+// checkCVSSConsistency verifies that each ScoreSet's vector string (when
+// present) names the same CVSS version as ScoreSet.Version, and that
+// BaseScore falls in CVSS's 0.0-10.0 range. It does not recompute BaseScore
+// from Vector, so a vector and score that are both well-formed but
+// mutually inconsistent will not be caught.
+func checkCVSSConsistency(rep Report) []Diagnostic {
+	var diags []Diagnostic
+	for vi, v := range rep.Vulnerabilities {
+		for si, ss := range v.CVSSScoreSets {
+			path := fmt.Sprintf("vulnerabilities[%d].cvssScoreSets[%d]", vi, si)
+			if ss.BaseScore < 0 || ss.BaseScore > 10 {
+				diags = append(diags, Diagnostic{
+					Severity: SeverityError,
+					Rule:     "cvss-score-range",
+					Path:     path + ".baseScore",
+					Message:  fmt.Sprintf("base score %v is outside the valid CVSS range of 0.0-10.0", ss.BaseScore),
+				})
+			}
+			if ss.Vector != "" && ss.Version != "" && !strings.Contains(ss.Vector, "CVSS:"+ss.Version) {
+				diags = append(diags, Diagnostic{
+					Severity: SeverityWarning,
+					Rule:     "cvss-vector-version-mismatch",
+					Path:     path + ".vector",
+					Message:  fmt.Sprintf("vector %q does not name CVSS version %q", ss.Vector, ss.Version),
+				})
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"testing"
+	"time"
+)
+
+func hasRule(diags []Diagnostic, rule string) bool {
+	for _, d := range diags {
+		if d.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckProductReferencesGroupID(t *testing.T) {
+	rep := Report{
+		ProductTree: ProductTree{
+			Groups: []Group{
+				{GroupID: "G1", ProductID: []string{"vendor:product"}},
+			},
+		},
+		Vulnerabilities: []Vulnerability{
+			{
+				Remediations: []Remediation{
+					{Type: "Fix", GroupID: []string{"G1"}},
+				},
+				Threats: []Threat{
+					{Type: "Impact", GroupID: []string{"G-missing"}},
+				},
+			},
+		},
+	}
+
+	diags := checkProductReferences(rep)
+	if hasRule(diags, "group-reference") == false {
+		t.Fatalf("expected a group-reference diagnostic for the undeclared GroupID %q, got %+v", "G-missing", diags)
+	}
+	for _, d := range diags {
+		if d.Rule == "group-reference" && d.Path == "vulnerabilities[0].remediations[0].groupID[0]" {
+			t.Errorf("declared GroupID %q should not have been flagged", "G1")
+		}
+	}
+}
+
+func TestCheckRevisionHistory(t *testing.T) {
+	rev := func(number string) Revision { return Revision{Number: number} }
+
+	cases := []struct {
+		name    string
+		revs    []Revision
+		version string
+		rules   []string
+	}{
+		{
+			name:    "clean",
+			revs:    []Revision{rev("1.0.0"), rev("1.1.0")},
+			version: "1.1.0",
+		},
+		{
+			name:  "no revisions",
+			revs:  nil,
+			rules: nil,
+		},
+		{
+			name:    "non-monotonic",
+			revs:    []Revision{rev("1.1.0"), rev("1.0.0")},
+			version: "1.0.0",
+			rules:   []string{"revision-history-monotonic"},
+		},
+		{
+			name:    "version mismatch",
+			revs:    []Revision{rev("1.0.0"), rev("1.1.0")},
+			version: "1.2.0",
+			rules:   []string{"revision-history-matches-version"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rep := Report{Doc: Document{Tracking: Tracking{RevisionHistory: c.revs, Version: c.version}}}
+			diags := checkRevisionHistory(rep)
+			for _, rule := range c.rules {
+				if !hasRule(diags, rule) {
+					t.Errorf("expected a %q diagnostic, got %+v", rule, diags)
+				}
+			}
+			if len(c.rules) == 0 && len(diags) != 0 {
+				t.Errorf("expected no diagnostics, got %+v", diags)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "1.1.0", -1},
+		{"1.1.0", "1.0.0", 1},
+		{"1.0", "1.0.0", 0},
+		{"abc", "1.0.0", 0},
+	}
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestCheckCSAFProfile(t *testing.T) {
+	cases := []struct {
+		name  string
+		rep   Report
+		rules []string
+	}{
+		{
+			name: "not a security advisory",
+			rep:  Report{Doc: Document{Type: "security_incident_response"}},
+		},
+		{
+			name:  "security advisory with no vulnerabilities",
+			rep:   Report{Doc: Document{Type: "security_advisory"}},
+			rules: []string{"csaf-security-advisory-profile"},
+		},
+		{
+			name: "security advisory with a vulnerability",
+			rep: Report{
+				Doc:             Document{Type: "security_advisory"},
+				Vulnerabilities: []Vulnerability{{CVE: "CVE-2024-0001"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := checkCSAFProfile(c.rep)
+			for _, rule := range c.rules {
+				if !hasRule(diags, rule) {
+					t.Errorf("expected a %q diagnostic, got %+v", rule, diags)
+				}
+			}
+			if len(c.rules) == 0 && len(diags) != 0 {
+				t.Errorf("expected no diagnostics, got %+v", diags)
+			}
+		})
+	}
+}
+
+func TestCheckCVSSConsistency(t *testing.T) {
+	cases := []struct {
+		name  string
+		score ScoreSet
+		rules []string
+	}{
+		{
+			name:  "clean",
+			score: ScoreSet{Version: "3.1", BaseScore: 9.8, Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+		},
+		{
+			name:  "score too high",
+			score: ScoreSet{Version: "3.1", BaseScore: 10.1, Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+			rules: []string{"cvss-score-range"},
+		},
+		{
+			name:  "score negative",
+			score: ScoreSet{Version: "3.1", BaseScore: -1},
+			rules: []string{"cvss-score-range"},
+		},
+		{
+			name:  "vector names a different version",
+			score: ScoreSet{Version: "3.1", BaseScore: 5.0, Vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+			rules: []string{"cvss-vector-version-mismatch"},
+		},
+		{
+			name:  "no vector to check",
+			score: ScoreSet{Version: "3.1", BaseScore: 5.0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rep := Report{Vulnerabilities: []Vulnerability{{CVSSScoreSets: []ScoreSet{c.score}}}}
+			diags := checkCVSSConsistency(rep)
+			for _, rule := range c.rules {
+				if !hasRule(diags, rule) {
+					t.Errorf("expected a %q diagnostic, got %+v", rule, diags)
+				}
+			}
+			if len(c.rules) == 0 && len(diags) != 0 {
+				t.Errorf("expected no diagnostics, got %+v", diags)
+			}
+		})
+	}
+}
+
+func TestValidateRunsProductReferenceCheck(t *testing.T) {
+	rep := Report{
+		Doc: Document{
+			Type:     "security_advisory",
+			Tracking: Tracking{Version: "1.0.0", RevisionHistory: []Revision{{Number: "1.0.0", Date: time.Now()}}},
+		},
+		Vulnerabilities: []Vulnerability{{
+			CVSSScoreSets: []ScoreSet{{Version: "3.1", BaseScore: 9.8, ProductID: []string{"missing"}}},
+		}},
+	}
+
+	diags := rep.Validate()
+	if !hasRule(diags, "product-reference") {
+		t.Errorf("expected Validate to run checkProductReferences, got %+v", diags)
+	}
+}
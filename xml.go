@@ -0,0 +1,448 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ParseXML reads a CVRF 1.2 document from r, producing a Report.
+func ParseXML(r io.Reader) (Report, error) {
+	var doc cvrfDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return Report{}, fmt.Errorf("vulnrep: parsing CVRF: %w", err)
+	}
+	return doc.toReport()
+}
+
+// ToCVRF writes rep to w as a CVRF 1.2 XML document.
+func (rep Report) ToCVRF(w io.Writer) error {
+	doc := fromReportToCVRF(rep)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("vulnrep: writing CVRF: %w", err)
+	}
+	return nil
+}
+
+// The cvrfDoc family of types is the wire representation of a CVRF 1.2
+// document - the shape dictated by the CVRF XML schema, as opposed to
+// Report, which is the shape convenient for callers.
+
+type cvrfDoc struct {
+	XMLName         xml.Name            `xml:"cvrfdoc"`
+	DocumentTitle   string              `xml:"DocumentTitle"`
+	DocumentType    string              `xml:"DocumentType"`
+	Publisher       cvrfPublisher       `xml:"DocumentPublisher"`
+	Tracking        cvrfTracking        `xml:"DocumentTracking"`
+	Notes           []cvrfNote          `xml:"DocumentNotes>Note"`
+	References      []cvrfReference     `xml:"DocumentReferences>Reference"`
+	Acknowledgments []cvrfAck           `xml:"Acknowledgments>Acknowledgment"`
+	ProductTree     cvrfProductTree     `xml:"ProductTree"`
+	Vulnerabilities []cvrfVulnerability `xml:"Vulnerability"`
+}
+
+type cvrfPublisher struct {
+	Type           string `xml:"Type,attr"`
+	ContactDetails string `xml:"ContactDetails"`
+	Name           string `xml:"VendorName"`
+	Namespace      string `xml:"VendorNamespace"`
+}
+
+type cvrfTracking struct {
+	ID                 string         `xml:"Identification>ID"`
+	Status             string         `xml:"Status"`
+	Version            string         `xml:"Version"`
+	Revisions          []cvrfRevision `xml:"RevisionHistory>Revision"`
+	InitialReleaseDate time.Time      `xml:"InitialReleaseDate"`
+	CurrentReleaseDate time.Time      `xml:"CurrentReleaseDate"`
+}
+
+type cvrfRevision struct {
+	Number      string    `xml:"Number"`
+	Date        time.Time `xml:"Date"`
+	Description string    `xml:"Description"`
+}
+
+type cvrfNote struct {
+	Title    string `xml:"Title,attr"`
+	Type     string `xml:"Type,attr"`
+	Audience string `xml:"Audience,attr"`
+	Text     string `xml:",chardata"`
+}
+
+type cvrfReference struct {
+	URL         string `xml:"URL"`
+	Description string `xml:"Description"`
+	Type        string `xml:"Type,attr"`
+}
+
+type cvrfAck struct {
+	Names        []string `xml:"Name"`
+	Organization string   `xml:"Organization"`
+	Description  string   `xml:"Description"`
+	URL          string   `xml:"URL"`
+}
+
+type cvrfProductTree struct {
+	Branches         []cvrfBranch          `xml:"Branch"`
+	FullProductNames []cvrfFullProductName `xml:"FullProductName"`
+	Relationships    []cvrfRelationship    `xml:"Relationship"`
+	Groups           []cvrfGroup           `xml:"ProductGroups>Group"`
+}
+
+type cvrfBranch struct {
+	Type     string               `xml:"Type,attr"`
+	Name     string               `xml:"Name,attr"`
+	Branches []cvrfBranch         `xml:"Branch"`
+	Product  *cvrfFullProductName `xml:"FullProductName"`
+}
+
+type cvrfFullProductName struct {
+	Name      string `xml:",chardata"`
+	ProductID string `xml:"ProductID,attr"`
+	CPE       string `xml:"CPE,attr"`
+}
+
+type cvrfRelationship struct {
+	ProductReference          string              `xml:"ProductReference,attr"`
+	RelationType              string              `xml:"RelationType,attr"`
+	RelatesToProductReference string              `xml:"RelatesToProductReference,attr"`
+	Product                   cvrfFullProductName `xml:"FullProductName"`
+}
+
+type cvrfGroup struct {
+	GroupID     string   `xml:"GroupID,attr"`
+	Description string   `xml:"Description"`
+	ProductID   []string `xml:"ProductID"`
+}
+
+type cvrfVulnerability struct {
+	Ordinal         int                 `xml:"Ordinal,attr"`
+	Title           string              `xml:"Title"`
+	ID              string              `xml:"ID"`
+	CVE             string              `xml:"CVE"`
+	CWE             *cvrfCWE            `xml:"CWE"`
+	DiscoveryDate   *time.Time          `xml:"DiscoveryDate"`
+	ReleaseDate     *time.Time          `xml:"ReleaseDate"`
+	Notes           []cvrfNote          `xml:"Notes>Note"`
+	References      []cvrfReference     `xml:"References>Reference"`
+	Acknowledgments []cvrfAck           `xml:"Acknowledgments>Acknowledgment"`
+	ProductStatuses []cvrfProductStatus `xml:"ProductStatuses>Status"`
+	Remediations    []cvrfRemediation   `xml:"Remediations>Remediation"`
+	Threats         []cvrfThreat        `xml:"Threats>Threat"`
+	Involvements    []cvrfInvolvement   `xml:"Involvements>Involvement"`
+	CVSSScoreSets   []cvrfScoreSet      `xml:"CVSSScoreSets>ScoreSet"`
+}
+
+type cvrfCWE struct {
+	ID   string `xml:"ID,attr"`
+	Name string `xml:",chardata"`
+}
+
+type cvrfProductStatus struct {
+	Type      string   `xml:"Type,attr"`
+	ProductID []string `xml:"ProductID"`
+}
+
+type cvrfRemediation struct {
+	Type        string   `xml:"Type,attr"`
+	Description string   `xml:"Description"`
+	URL         string   `xml:"URL"`
+	ProductID   []string `xml:"ProductID"`
+	GroupID     []string `xml:"GroupID"`
+}
+
+type cvrfThreat struct {
+	Type        string     `xml:"Type,attr"`
+	Description string     `xml:"Description"`
+	Date        *time.Time `xml:"Date"`
+	ProductID   []string   `xml:"ProductID"`
+	GroupID     []string   `xml:"GroupID"`
+}
+
+type cvrfInvolvement struct {
+	Party       string `xml:"Party,attr"`
+	Status      string `xml:"Status,attr"`
+	Description string `xml:"Description"`
+}
+
+type cvrfScoreSet struct {
+	Version   string   `xml:"Version,attr"`
+	BaseScore float64  `xml:"BaseScoreV3"`
+	Vector    string   `xml:"VectorV3"`
+	ProductID []string `xml:"ProductID"`
+}
+
+func (doc cvrfDoc) toReport() (Report, error) {
+	rep := Report{
+		Doc: Document{
+			Title: doc.DocumentTitle,
+			Type:  doc.DocumentType,
+			Publisher: Publisher{
+				Name:      doc.Publisher.Name,
+				Namespace: doc.Publisher.Namespace,
+				Type:      doc.Publisher.Type,
+			},
+			Tracking: Tracking{
+				ID:                 doc.Tracking.ID,
+				Status:             doc.Tracking.Status,
+				Version:            doc.Tracking.Version,
+				InitialReleaseDate: doc.Tracking.InitialReleaseDate,
+				CurrentReleaseDate: doc.Tracking.CurrentReleaseDate,
+			},
+		},
+	}
+	for _, rev := range doc.Tracking.Revisions {
+		rep.Doc.Tracking.RevisionHistory = append(rep.Doc.Tracking.RevisionHistory, Revision{
+			Number:      rev.Number,
+			Date:        rev.Date,
+			Description: rev.Description,
+		})
+	}
+	for _, n := range doc.Notes {
+		rep.Doc.Notes = append(rep.Doc.Notes, Note{Title: n.Title, Type: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range doc.References {
+		rep.Doc.References = append(rep.Doc.References, Reference{URL: r.URL, Description: r.Description, Type: r.Type})
+	}
+	for _, a := range doc.Acknowledgments {
+		rep.Doc.Acknowledgments = append(rep.Doc.Acknowledgments, Acknowledgment{
+			Names: a.Names, Organization: a.Organization, Description: a.Description, URL: a.URL,
+		})
+	}
+
+	rep.ProductTree = fromCVRFProductTree(doc.ProductTree)
+
+	for _, v := range doc.Vulnerabilities {
+		rep.Vulnerabilities = append(rep.Vulnerabilities, fromCVRFVulnerability(v))
+	}
+	return rep, nil
+}
+
+func fromCVRFProductTree(pt cvrfProductTree) ProductTree {
+	out := ProductTree{}
+	for _, b := range pt.Branches {
+		out.Branches = append(out.Branches, fromCVRFBranch(b))
+	}
+	for _, fpn := range pt.FullProductNames {
+		out.FullProductNames = append(out.FullProductNames, fromCVRFFullProductName(fpn))
+	}
+	for _, rel := range pt.Relationships {
+		out.Relationships = append(out.Relationships, Relationship{
+			ProductReference:          rel.ProductReference,
+			RelationType:              rel.RelationType,
+			RelatesToProductReference: rel.RelatesToProductReference,
+			ProductID:                 rel.Product.ProductID,
+			Name:                      rel.Product.Name,
+		})
+	}
+	for _, g := range pt.Groups {
+		out.Groups = append(out.Groups, Group{GroupID: g.GroupID, Description: g.Description, ProductID: g.ProductID})
+	}
+	return out
+}
+
+func fromCVRFBranch(b cvrfBranch) Branch {
+	out := Branch{Type: b.Type, Name: b.Name}
+	for _, sub := range b.Branches {
+		out.Branches = append(out.Branches, fromCVRFBranch(sub))
+	}
+	if b.Product != nil {
+		p := fromCVRFFullProductName(*b.Product)
+		out.Product = &p
+	}
+	return out
+}
+
+func fromCVRFFullProductName(fpn cvrfFullProductName) FullProductName {
+	return FullProductName{Name: fpn.Name, ProductID: fpn.ProductID, CPE: fpn.CPE}
+}
+
+func fromCVRFVulnerability(v cvrfVulnerability) Vulnerability {
+	out := Vulnerability{
+		Ordinal: v.Ordinal,
+		Title:   v.Title,
+		ID:      v.ID,
+		CVE:     v.CVE,
+	}
+	if v.CWE != nil {
+		out.CWE = CWE{ID: v.CWE.ID, Name: v.CWE.Name}
+	}
+	if v.DiscoveryDate != nil {
+		out.DiscoveryDate = *v.DiscoveryDate
+	}
+	if v.ReleaseDate != nil {
+		out.ReleaseDate = *v.ReleaseDate
+	}
+	for _, n := range v.Notes {
+		out.Notes = append(out.Notes, Note{Title: n.Title, Type: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range v.References {
+		out.References = append(out.References, Reference{URL: r.URL, Description: r.Description, Type: r.Type})
+	}
+	for _, a := range v.Acknowledgments {
+		out.Acknowledgments = append(out.Acknowledgments, Acknowledgment{
+			Names: a.Names, Organization: a.Organization, Description: a.Description, URL: a.URL,
+		})
+	}
+	for _, s := range v.ProductStatuses {
+		out.ProductStatuses = append(out.ProductStatuses, ProductStatus{Type: s.Type, ProductID: s.ProductID})
+	}
+	for _, rem := range v.Remediations {
+		out.Remediations = append(out.Remediations, Remediation{
+			Type: rem.Type, Description: rem.Description, URL: rem.URL, ProductID: rem.ProductID, GroupID: rem.GroupID,
+		})
+	}
+	for _, t := range v.Threats {
+		th := Threat{Type: t.Type, Description: t.Description, ProductID: t.ProductID, GroupID: t.GroupID}
+		if t.Date != nil {
+			th.Date = *t.Date
+		}
+		out.Threats = append(out.Threats, th)
+	}
+	for _, inv := range v.Involvements {
+		out.Involvements = append(out.Involvements, Involvement{Party: inv.Party, Status: inv.Status, Description: inv.Description})
+	}
+	for _, ss := range v.CVSSScoreSets {
+		out.CVSSScoreSets = append(out.CVSSScoreSets, ScoreSet{
+			Version: ss.Version, BaseScore: ss.BaseScore, Vector: ss.Vector, ProductID: ss.ProductID,
+		})
+	}
+	return out
+}
+
+func fromReportToCVRF(rep Report) cvrfDoc {
+	doc := cvrfDoc{
+		DocumentTitle: rep.Doc.Title,
+		DocumentType:  rep.Doc.Type,
+		Publisher: cvrfPublisher{
+			Type:      rep.Doc.Publisher.Type,
+			Name:      rep.Doc.Publisher.Name,
+			Namespace: rep.Doc.Publisher.Namespace,
+		},
+		Tracking: cvrfTracking{
+			ID:                 rep.Doc.Tracking.ID,
+			Status:             rep.Doc.Tracking.Status,
+			Version:            rep.Doc.Tracking.Version,
+			InitialReleaseDate: rep.Doc.Tracking.InitialReleaseDate,
+			CurrentReleaseDate: rep.Doc.Tracking.CurrentReleaseDate,
+		},
+	}
+	for _, rev := range rep.Doc.Tracking.RevisionHistory {
+		doc.Tracking.Revisions = append(doc.Tracking.Revisions, cvrfRevision{
+			Number: rev.Number, Date: rev.Date, Description: rev.Description,
+		})
+	}
+	for _, n := range rep.Doc.Notes {
+		doc.Notes = append(doc.Notes, cvrfNote{Title: n.Title, Type: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range rep.Doc.References {
+		doc.References = append(doc.References, cvrfReference{URL: r.URL, Description: r.Description, Type: r.Type})
+	}
+	for _, a := range rep.Doc.Acknowledgments {
+		doc.Acknowledgments = append(doc.Acknowledgments, cvrfAck{
+			Names: a.Names, Organization: a.Organization, Description: a.Description, URL: a.URL,
+		})
+	}
+
+	doc.ProductTree = toCVRFProductTree(rep.ProductTree)
+
+	for _, v := range rep.Vulnerabilities {
+		doc.Vulnerabilities = append(doc.Vulnerabilities, toCVRFVulnerability(v))
+	}
+	return doc
+}
+
+func toCVRFProductTree(pt ProductTree) cvrfProductTree {
+	out := cvrfProductTree{}
+	for _, b := range pt.Branches {
+		out.Branches = append(out.Branches, toCVRFBranch(b))
+	}
+	for _, fpn := range pt.FullProductNames {
+		out.FullProductNames = append(out.FullProductNames, cvrfFullProductName{Name: fpn.Name, ProductID: fpn.ProductID, CPE: fpn.CPE})
+	}
+	for _, rel := range pt.Relationships {
+		out.Relationships = append(out.Relationships, cvrfRelationship{
+			ProductReference:          rel.ProductReference,
+			RelationType:              rel.RelationType,
+			RelatesToProductReference: rel.RelatesToProductReference,
+			Product:                   cvrfFullProductName{Name: rel.Name, ProductID: rel.ProductID},
+		})
+	}
+	for _, g := range pt.Groups {
+		out.Groups = append(out.Groups, cvrfGroup{GroupID: g.GroupID, Description: g.Description, ProductID: g.ProductID})
+	}
+	return out
+}
+
+func toCVRFBranch(b Branch) cvrfBranch {
+	out := cvrfBranch{Type: b.Type, Name: b.Name}
+	for _, sub := range b.Branches {
+		out.Branches = append(out.Branches, toCVRFBranch(sub))
+	}
+	if b.Product != nil {
+		out.Product = &cvrfFullProductName{Name: b.Product.Name, ProductID: b.Product.ProductID, CPE: b.Product.CPE}
+	}
+	return out
+}
+
+func toCVRFVulnerability(v Vulnerability) cvrfVulnerability {
+	out := cvrfVulnerability{
+		Ordinal: v.Ordinal,
+		Title:   v.Title,
+		ID:      v.ID,
+		CVE:     v.CVE,
+	}
+	if v.CWE.ID != "" {
+		out.CWE = &cvrfCWE{ID: v.CWE.ID, Name: v.CWE.Name}
+	}
+	if !v.DiscoveryDate.IsZero() {
+		out.DiscoveryDate = &v.DiscoveryDate
+	}
+	if !v.ReleaseDate.IsZero() {
+		out.ReleaseDate = &v.ReleaseDate
+	}
+	for _, n := range v.Notes {
+		out.Notes = append(out.Notes, cvrfNote{Title: n.Title, Type: n.Type, Audience: n.Audience, Text: n.Text})
+	}
+	for _, r := range v.References {
+		out.References = append(out.References, cvrfReference{URL: r.URL, Description: r.Description, Type: r.Type})
+	}
+	for _, a := range v.Acknowledgments {
+		out.Acknowledgments = append(out.Acknowledgments, cvrfAck{
+			Names: a.Names, Organization: a.Organization, Description: a.Description, URL: a.URL,
+		})
+	}
+	for _, s := range v.ProductStatuses {
+		out.ProductStatuses = append(out.ProductStatuses, cvrfProductStatus{Type: s.Type, ProductID: s.ProductID})
+	}
+	for _, rem := range v.Remediations {
+		out.Remediations = append(out.Remediations, cvrfRemediation{
+			Type: rem.Type, Description: rem.Description, URL: rem.URL, ProductID: rem.ProductID, GroupID: rem.GroupID,
+		})
+	}
+	for _, t := range v.Threats {
+		ct := cvrfThreat{Type: t.Type, Description: t.Description, ProductID: t.ProductID, GroupID: t.GroupID}
+		if !t.Date.IsZero() {
+			ct.Date = &t.Date
+		}
+		out.Threats = append(out.Threats, ct)
+	}
+	for _, inv := range v.Involvements {
+		out.Involvements = append(out.Involvements, cvrfInvolvement{Party: inv.Party, Status: inv.Status, Description: inv.Description})
+	}
+	for _, ss := range v.CVSSScoreSets {
+		out.CVSSScoreSets = append(out.CVSSScoreSets, cvrfScoreSet{
+			Version: ss.Version, BaseScore: ss.BaseScore, Vector: ss.Vector, ProductID: ss.ProductID,
+		})
+	}
+	return out
+}
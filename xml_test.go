@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//
+// Copyright 2019, TIBCO Software Inc. This file is subject to the license
+// terms contained in the license file that is distributed with this file.
+
+package vulnrep
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sampleReport returns a Report exercising most fields a CVRF or CSAF
+// document can carry, for use by the format round-trip tests.
+func sampleReport() Report {
+	initial := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	released := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	discovered := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	threatened := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	return Report{
+		Doc: Document{
+			Title: "Example Vendor Security Advisory",
+			Type:  "security_advisory",
+			Publisher: Publisher{
+				Name:      "Example Vendor",
+				Namespace: "https://example.com",
+				Type:      "Vendor",
+			},
+			Tracking: Tracking{
+				ID:      "EXAMPLE-2024-0001",
+				Status:  "Final",
+				Version: "1.0.0",
+				RevisionHistory: []Revision{
+					{Number: "1.0.0", Date: released, Description: "Initial release"},
+				},
+				InitialReleaseDate: initial,
+				CurrentReleaseDate: released,
+			},
+			Notes: []Note{
+				{Title: "Summary", Type: "summary", Text: "An example vulnerability in ProductX."},
+			},
+			References: []Reference{
+				{URL: "https://example.com/advisory/EXAMPLE-2024-0001", Description: "Advisory", Type: "External"},
+			},
+			Acknowledgments: []Acknowledgment{
+				{Names: []string{"Jane Doe"}, Organization: "Example Org", Description: "reported the issue"},
+			},
+		},
+		ProductTree: ProductTree{
+			Branches: []Branch{
+				{
+					Type: "Vendor",
+					Name: "Example Vendor",
+					Branches: []Branch{
+						{
+							Type: "Product",
+							Name: "ProductX",
+							Product: &FullProductName{
+								Name:      "ProductX",
+								ProductID: "CSAFPID-0001",
+								CPE:       "cpe:2.3:a:example:productx:*:*:*:*:*:*:*:*",
+							},
+						},
+					},
+				},
+			},
+			Groups: []Group{
+				{GroupID: "CSAFGID-0001", Description: "Affected products", ProductID: []string{"CSAFPID-0001"}},
+			},
+		},
+		Vulnerabilities: []Vulnerability{
+			{
+				Ordinal:       0,
+				Title:         "Example Buffer Overflow",
+				ID:            "CVE-2024-0001",
+				CVE:           "CVE-2024-0001",
+				CWE:           CWE{ID: "CWE-120", Name: "Buffer Copy without Checking Size of Input"},
+				DiscoveryDate: discovered,
+				ReleaseDate:   released,
+				Notes: []Note{
+					{Title: "Details", Type: "general", Text: "An attacker can trigger a buffer overflow by sending a crafted request."},
+				},
+				References: []Reference{
+					{URL: "https://example.com/advisory/EXAMPLE-2024-0001#cve-2024-0001", Description: "Vulnerability details", Type: "External"},
+				},
+				Acknowledgments: []Acknowledgment{
+					{Names: []string{"Jane Doe"}, Organization: "Example Org", Description: "found and reported the issue"},
+				},
+				ProductStatuses: []ProductStatus{
+					{Type: "Known Affected", ProductID: []string{"CSAFPID-0001"}},
+				},
+				Remediations: []Remediation{
+					{Type: "Vendor Fix", Description: "Apply the vendor patch", URL: "https://example.com/patch", ProductID: []string{"CSAFPID-0001"}, GroupID: []string{"CSAFGID-0001"}},
+				},
+				Threats: []Threat{
+					{Type: "Impact", Description: "Remote code execution", Date: threatened, ProductID: []string{"CSAFPID-0001"}, GroupID: []string{"CSAFGID-0001"}},
+				},
+				Involvements: []Involvement{
+					{Party: "Vendor", Status: "Completed", Description: "Fix released"},
+				},
+				CVSSScoreSets: []ScoreSet{
+					{Version: "3.1", BaseScore: 9.8, Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", ProductID: []string{"CSAFPID-0001"}},
+				},
+			},
+		},
+	}
+}
+
+func TestParseXMLFixture(t *testing.T) {
+	f, err := os.Open("testdata/report.cvrf.xml")
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	rep, err := ParseXML(f)
+	if err != nil {
+		t.Fatalf("ParseXML: %v", err)
+	}
+
+	if rep.Doc.Title != "Example Vendor Security Advisory" {
+		t.Errorf("Doc.Title = %q", rep.Doc.Title)
+	}
+	if rep.Doc.Tracking.ID != "EXAMPLE-2024-0001" {
+		t.Errorf("Doc.Tracking.ID = %q", rep.Doc.Tracking.ID)
+	}
+	if len(rep.Doc.Tracking.RevisionHistory) != 1 || rep.Doc.Tracking.RevisionHistory[0].Number != "1.0.0" {
+		t.Errorf("Doc.Tracking.RevisionHistory = %+v", rep.Doc.Tracking.RevisionHistory)
+	}
+	if len(rep.ProductTree.Groups) != 1 || rep.ProductTree.Groups[0].GroupID != "CSAFGID-0001" {
+		t.Fatalf("ProductTree.Groups = %+v", rep.ProductTree.Groups)
+	}
+	if got := rep.ProductTree.Groups[0].ProductID; len(got) != 1 || got[0] != "CSAFPID-0001" {
+		t.Errorf("Groups[0].ProductID = %v", got)
+	}
+	if len(rep.Vulnerabilities) != 1 {
+		t.Fatalf("len(Vulnerabilities) = %d, want 1", len(rep.Vulnerabilities))
+	}
+	v := rep.Vulnerabilities[0]
+	if v.CVE != "CVE-2024-0001" || v.ID != "CVE-2024-0001" {
+		t.Errorf("Vulnerability CVE/ID = %q/%q", v.CVE, v.ID)
+	}
+	if v.CWE.ID != "CWE-120" {
+		t.Errorf("Vulnerability.CWE.ID = %q", v.CWE.ID)
+	}
+	if len(v.Remediations) != 1 || v.Remediations[0].GroupID[0] != "CSAFGID-0001" {
+		t.Errorf("Vulnerability.Remediations = %+v", v.Remediations)
+	}
+	if len(v.CVSSScoreSets) != 1 || v.CVSSScoreSets[0].BaseScore != 9.8 {
+		t.Errorf("Vulnerability.CVSSScoreSets = %+v", v.CVSSScoreSets)
+	}
+
+	if diags := rep.Validate(); len(diags) != 0 {
+		t.Errorf("Validate() = %+v, want none", diags)
+	}
+}
+
+func TestCVRFRoundTrip(t *testing.T) {
+	want := sampleReport()
+
+	var buf bytes.Buffer
+	if err := want.ToCVRF(&buf); err != nil {
+		t.Fatalf("ToCVRF: %v", err)
+	}
+
+	got, err := ParseXML(&buf)
+	if err != nil {
+		t.Fatalf("ParseXML: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("round trip through CVRF changed the report:\n got:  %+v\n want: %+v", got, want)
+	}
+}